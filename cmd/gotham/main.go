@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gorilla/mux"
 	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/mailer"
+	"github.com/jeffemart/Gotham/internal/policy"
+	"github.com/jeffemart/Gotham/internal/rbac"
 	"github.com/jeffemart/Gotham/internal/routes"
 	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/internal/utils"
 	"github.com/jeffemart/Gotham/migrations"
 	_ "github.com/jeffemart/Gotham/docs"
 )
@@ -41,6 +49,33 @@ func main() {
 	// @tag.name auth
 	// @tag.description Operações de autenticação
 
+	// @tag.name sessions
+	// @tag.description Gerenciamento de sessões ativas
+
+	// @tag.name mfa
+	// @tag.description Autenticação multifator (TOTP)
+
+	// @tag.name roles
+	// @tag.description Gerenciamento de roles, permissões e capacidades
+
+	// @tag.name lockouts
+	// @tag.description Bloqueios de login por excesso de falhas
+
+	// @tag.name organizations
+	// @tag.description Organizações (tenants) e os seus membros
+
+	// @tag.name groups
+	// @tag.description Grupos de usuários e as roles que eles recebem
+
+	// @tag.name grants
+	// @tag.description Concessões temporárias de roles, com solicitação e aprovação
+
+	// @tag.name me
+	// @tag.description Perfil do usuário autenticado
+
+	// @tag.name authz
+	// @tag.description Decisões de autorização para outros serviços
+
 	// Carregar configurações
 	config := settings.LoadSettings()
 
@@ -52,6 +87,47 @@ func main() {
 		log.Fatalf("Erro ao executar migração: %v", err)
 	}
 
+	// Configurar o envio de e-mails conforme MAIL_DRIVER
+	m, err := mailer.New(config)
+	if err != nil {
+		log.Fatalf("Erro ao configurar e-mail: %v", err)
+	}
+	mailer.Default = m
+
+	// Cache das roles resolvidas, invalidado pelo Redis quando qualquer instância altera uma role
+	rbac.DefaultCache = rbac.NewCache(config.RBAC.CacheSize, config.RBAC.CacheTTL)
+	if err := rbac.SubscribeInvalidations(context.Background()); err != nil {
+		log.Fatalf("Erro ao assinar invalidações do cache de roles: %v", err)
+	}
+
+	// Marcar no Redis as contas que não estão ativas, consultadas a cada requisição autenticada
+	if count, err := utils.SyncInactiveUsers(); err != nil {
+		log.Fatalf("Erro ao sincronizar a situação das contas: %v", err)
+	} else if count > 0 {
+		log.Printf("%d conta(s) inativa(s) marcada(s) no Redis", count)
+	}
+
+	// Remover definitivamente os usuários que estão na lixeira há mais que o período de retenção
+	utils.StartUserPurge(context.Background(), config.Users.PurgeInterval, config.Users.DeletedRetention)
+
+	// Carregar as chaves de assinatura dos tokens
+	if _, err := utils.LoadKeyring(); err != nil {
+		log.Fatalf("Erro ao carregar chaves de assinatura: %v", err)
+	}
+
+	// Recarregar as chaves ao receber SIGHUP (após uma rotação com cmd/jwtkeys)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if _, err := utils.LoadKeyring(); err != nil {
+				log.Printf("Erro ao recarregar chaves de assinatura: %v", err)
+				continue
+			}
+			log.Println("Chaves de assinatura recarregadas.")
+		}
+	}()
+
 	// Criar o roteador principal
 	r := mux.NewRouter()
 
@@ -61,6 +137,11 @@ func main() {
 	// Rota para servir a documentação Swagger
 	r.PathPrefix("/swagger/").Handler(http.StripPrefix("/swagger/", http.FileServer(http.Dir("./docs/swagger"))))
 
+	// Toda rota registrada precisa de uma regra na política de acesso, e vice-versa
+	if err := policy.Default.Verify(r); err != nil {
+		log.Fatalf("Erro ao carregar política de acesso: %v", err)
+	}
+
 	// Iniciar servidor na porta configurada
 	port := config.App.Port
 	log.Printf("Servidor iniciado em http://localhost:%s", port)
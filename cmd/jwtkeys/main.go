@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jeffemart/Gotham/internal/keyring"
+	"github.com/jeffemart/Gotham/internal/settings"
+)
+
+// jwtkeys gerencia as chaves de assinatura dos tokens JWT.
+//
+// Uso:
+//
+//	jwtkeys list                      lista as chaves (a ativa é marcada com *)
+//	jwtkeys add [-alg RS256|ES256]    gera uma nova chave, publicada no JWKS mas ainda não ativa
+//	jwtkeys activate <kid>            torna ativa uma chave publicada; a anterior passa a ser aposentada
+//	jwtkeys rotate [-alg RS256|ES256] gera uma nova chave e a ativa imediatamente (uma única instância)
+//	jwtkeys retire <kid>              remove uma chave que não está ativa
+//
+// Após cada etapa, envie SIGHUP aos servidores (ou reinicie-os). Com várias instâncias, ative a chave só depois
+// que todas a tiverem carregado e os caches de JWKS expirarem; caso contrário, os tokens assinados por ela são
+// recusados pelas instâncias que ainda não a conhecem.
+func main() {
+	config := settings.LoadSettings()
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "list":
+		ring, err := keyring.Load(config.JWT.KeysDir)
+		if err != nil {
+			log.Fatalf("Erro ao carregar chaves: %v", err)
+		}
+		for _, key := range ring.Keys() {
+			marker := " "
+			if ring.Active != nil && ring.Active.ID == key.ID {
+				marker = "*"
+			}
+			fmt.Printf("%s %s %s\n", marker, key.ID, key.Algorithm)
+		}
+
+	case "add":
+		flags := flag.NewFlagSet("add", flag.ExitOnError)
+		algorithm := flags.String("alg", config.JWT.Algorithm, "algoritmo da nova chave (RS256 ou ES256)")
+		flags.Parse(os.Args[2:])
+
+		key, err := keyring.Add(config.JWT.KeysDir, *algorithm)
+		if err != nil {
+			log.Fatalf("Erro ao gerar chave: %v", err)
+		}
+		fmt.Printf("Nova chave publicada: %s (%s)\n", key.ID, key.Algorithm)
+
+	case "activate":
+		if len(os.Args) < 3 {
+			usage()
+		}
+		key, err := keyring.Activate(config.JWT.KeysDir, os.Args[2])
+		if err != nil {
+			log.Fatalf("Erro ao ativar chave: %v", err)
+		}
+		fmt.Printf("Nova chave ativa: %s (%s)\n", key.ID, key.Algorithm)
+
+	case "rotate":
+		flags := flag.NewFlagSet("rotate", flag.ExitOnError)
+		algorithm := flags.String("alg", config.JWT.Algorithm, "algoritmo da nova chave (RS256 ou ES256)")
+		flags.Parse(os.Args[2:])
+
+		key, err := keyring.Rotate(config.JWT.KeysDir, *algorithm)
+		if err != nil {
+			log.Fatalf("Erro ao rotacionar chave: %v", err)
+		}
+		fmt.Printf("Nova chave ativa: %s (%s)\n", key.ID, key.Algorithm)
+
+	case "retire":
+		if len(os.Args) < 3 {
+			usage()
+		}
+		if err := keyring.Retire(config.JWT.KeysDir, os.Args[2]); err != nil {
+			log.Fatalf("Erro ao remover chave: %v", err)
+		}
+		fmt.Printf("Chave %s removida\n", os.Args[2])
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "uso: jwtkeys list | add [-alg RS256|ES256] | activate <kid> | rotate [-alg RS256|ES256] | retire <kid>")
+	os.Exit(2)
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/policy"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
+)
+
+// maxBatchChecks limita o número de verificações em uma única requisição em lote
+const maxBatchChecks = 100
+
+// AuthzBatchResponse traz as decisões na mesma ordem das verificações pedidas
+type AuthzBatchResponse struct {
+	Results []policy.Decision `json:"results"`
+}
+
+// CheckAuthorization decide se o sujeito pode executar a ação
+// @Summary Verifica uma autorização
+// @Description Permite que outros serviços reutilizem o RBAC: decide a ação ("verbo:recurso[:escopo]") para o sujeito
+// @Description (token ou usuário) com as mesmas regras da API. Com um recurso, o dono define o escopo exigido: o próprio
+// @Description recurso aceita "verbo:recurso:own" e os demais exigem "verbo:recurso:any". Sem sujeito, vale o usuário
+// @Description autenticado; outros sujeitos exigem check:authz. Sujeitos inexistentes ou inativos e tokens inválidos resultam em negação.
+// @Tags authz
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param request body models.AuthzCheckRequest true "Sujeito, ação e recurso"
+// @Success 200 {object} policy.Decision "Decisão"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 403 {string} string "Capacidades insuficientes para verificar outro sujeito"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /authz/check [post]
+func CheckAuthorization(w http.ResponseWriter, r *http.Request) {
+	var request models.AuthzCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	errs := validator.Errors{}
+	validateAuthzSubject(request.Subject, errs)
+	validateAuthzCheck("action", request.AuthzCheck, errs)
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	subject, ok := resolveAuthzSubject(w, r, request.Subject)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subject.decide(request.AuthzCheck))
+}
+
+// CheckAuthorizationBatch decide várias ações para o mesmo sujeito
+// @Summary Verifica autorizações em lote
+// @Description Como /authz/check, para até 100 ações do mesmo sujeito (por exemplo, para decidir o que exibir em uma tela).
+// @Description As decisões são retornadas na ordem das verificações.
+// @Tags authz
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param request body models.AuthzBatchRequest true "Sujeito e verificações"
+// @Success 200 {object} handlers.AuthzBatchResponse "Decisões"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 403 {string} string "Capacidades insuficientes para verificar outro sujeito"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /authz/check/batch [post]
+func CheckAuthorizationBatch(w http.ResponseWriter, r *http.Request) {
+	var request models.AuthzBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	errs := validator.Errors{}
+	validateAuthzSubject(request.Subject, errs)
+	switch {
+	case len(request.Checks) == 0:
+		errs.Add("checks", "Informe ao menos uma verificação")
+	case len(request.Checks) > maxBatchChecks:
+		errs.Add("checks", "Informe no máximo 100 verificações")
+	}
+	for _, check := range request.Checks {
+		validateAuthzCheck("checks", check, errs)
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	subject, ok := resolveAuthzSubject(w, r, request.Subject)
+	if !ok {
+		return
+	}
+
+	response := AuthzBatchResponse{Results: make([]policy.Decision, 0, len(request.Checks))}
+	for _, check := range request.Checks {
+		response.Results = append(response.Results, subject.decide(check))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// authzSubject é o sujeito resolvido; sem role, todas as decisões são negadas pelo motivo informado
+type authzSubject struct {
+	userID uint
+	role   *rbac.ResolvedRole
+	denied string
+}
+
+// decide toma a decisão da verificação para o sujeito
+func (s authzSubject) decide(check models.AuthzCheck) policy.Decision {
+	action := strings.TrimSpace(check.Action)
+	if s.role == nil {
+		return policy.Decision{Capability: action, Reason: s.denied}
+	}
+	if check.Resource != nil {
+		return policy.Decide(s.role.Capabilities, s.userID, action, check.Resource.OwnerID)
+	}
+	return policy.DecideCapability(s.role.Capabilities, action)
+}
+
+// resolveAuthzSubject resolve as capacidades efetivas do sujeito. Outro sujeito que não o usuário autenticado
+// exige check:authz e, em uma organização, só é resolvido dentro dela.
+func resolveAuthzSubject(w http.ResponseWriter, r *http.Request, subject models.AuthzSubject) (authzSubject, bool) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return authzSubject{}, false
+	}
+
+	if subject.Token == "" && subject.UserID == 0 {
+		role, err := utils.ResolveClaims(claims)
+		if err != nil {
+			return authzSubject{denied: "role não encontrada"}, true
+		}
+		return authzSubject{userID: claims.UserID, role: role}, true
+	}
+
+	if decision := policy.Can(claims, models.CapabilityCheckAuthz, 0); !decision.Allowed {
+		http.Error(w, "Acesso negado: verificar outro sujeito exige "+models.CapabilityCheckAuthz, http.StatusForbidden)
+		return authzSubject{}, false
+	}
+
+	if subject.Token != "" {
+		subjectClaims, err := utils.ValidateToken(subject.Token)
+		if err != nil {
+			return authzSubject{denied: "token inválido, expirado ou revogado"}, true
+		}
+		if organizationID := callerOrg(r); organizationID != 0 && subjectClaims.OrgID != organizationID {
+			return authzSubject{denied: "o token pertence a outra organização"}, true
+		}
+		if status, err := utils.InactiveStatus(subjectClaims.UserID); err != nil || status != "" {
+			return authzSubject{denied: "a conta não está ativa"}, true
+		}
+		role, err := utils.ResolveClaims(subjectClaims)
+		if err != nil {
+			return authzSubject{denied: "role não encontrada"}, true
+		}
+		return authzSubject{userID: subjectClaims.UserID, role: role}, true
+	}
+
+	organizationID := subject.OrganizationID
+	if callerOrganization := callerOrg(r); callerOrganization != 0 {
+		organizationID = callerOrganization
+	}
+
+	var user models.User
+	if err := usersQuery(r).First(&user, subject.UserID).Error; err != nil {
+		return authzSubject{denied: "usuário não encontrado"}, true
+	}
+	if !user.Active() {
+		return authzSubject{denied: "a conta não está ativa"}, true
+	}
+	roleID := user.RoleID
+	if organizationID != 0 {
+		membership, err := utils.FindMembership(user.ID, organizationID)
+		if errors.Is(err, utils.ErrNotMember) {
+			return authzSubject{denied: "o usuário não pertence à organização"}, true
+		}
+		if err != nil {
+			http.Error(w, "Erro ao buscar vínculo com a organização", http.StatusInternalServerError)
+			return authzSubject{}, false
+		}
+		roleID = membership.RoleID
+	}
+
+	role, err := rbac.ResolveUser(user.ID, organizationID, roleID)
+	if err != nil {
+		return authzSubject{denied: "role não encontrada"}, true
+	}
+	return authzSubject{userID: user.ID, role: role}, true
+}
+
+// validateAuthzSubject aceita no máximo uma forma de identificar o sujeito
+func validateAuthzSubject(subject models.AuthzSubject, errs validator.Errors) {
+	if subject.Token != "" && subject.UserID != 0 {
+		errs.Add("subject", "Informe o token ou o user_id, não os dois")
+	}
+	if subject.OrganizationID != 0 && subject.UserID == 0 {
+		errs.Add("subject", "organization_id só pode ser informado com user_id")
+	}
+}
+
+// validateAuthzCheck verifica o formato da ação, registrando os erros no campo informado
+func validateAuthzCheck(field string, check models.AuthzCheck, errs validator.Errors) {
+	action := strings.TrimSpace(check.Action)
+	if action == "" {
+		errs.Add(field, "A ação é obrigatória")
+		return
+	}
+	if rbac.IsDenial(action) || strings.Contains(action, "*") {
+		errs.Add(field, "Ação inválida: "+action)
+	}
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"gorm.io/gorm"
+)
+
+// GetDeletedUsers lista os usuários na lixeira
+// @Summary Lista os usuários excluídos
+// @Description Retorna os usuários excluídos que ainda não foram removidos definitivamente, dos mais recentes
+// @Description aos mais antigos, com o instante da remoção automática. Disponível apenas no escopo global.
+// @Tags users
+// @Security BearerAuth
+// @Produce  json
+// @Param page query int false "Número da página" default(1)
+// @Param limit query int false "Itens por página (no máximo 100)" default(10)
+// @Success 200 {object} models.PaginatedResponse{data=[]models.DeletedUserResponse}
+// @Failure 403 {string} string "Disponível apenas no escopo global"
+// @Failure 500 {string} string "Erro ao buscar usuários excluídos"
+// @Router /admin/users/deleted [get]
+func GetDeletedUsers(w http.ResponseWriter, r *http.Request) {
+	if !requireGlobalScope(w, r) {
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	query := database.DB.Unscoped().Model(&models.User{}).Where("users.deleted_at IS NOT NULL")
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		http.Error(w, "Erro ao buscar usuários excluídos", http.StatusInternalServerError)
+		return
+	}
+
+	var users []models.User
+	err := query.Preload("Role", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("users.deleted_at DESC, users.id").Limit(limit).Offset((page - 1) * limit).Find(&users).Error
+	if err != nil {
+		http.Error(w, "Erro ao buscar usuários excluídos", http.StatusInternalServerError)
+		return
+	}
+
+	retention := settings.LoadSettings().Users.DeletedRetention
+	deleted := make([]models.DeletedUserResponse, len(users))
+	for i, user := range users {
+		deleted[i] = models.DeletedUserResponse{
+			AdminUserResponse: models.NewAdminUserResponse(user),
+			DeletedAt:         user.DeletedAt.Time,
+			PurgeAt:           user.DeletedAt.Time.Add(retention),
+		}
+	}
+
+	total := int(totalCount)
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.PaginatedResponse{
+		Status:      http.StatusOK,
+		Message:     "Usuários excluídos encontrados",
+		Data:        deleted,
+		TotalCount:  &total,
+		TotalPages:  &totalPages,
+		CurrentPage: page,
+		Limit:       limit,
+	})
+}
+
+// RestoreUser restaura um usuário da lixeira
+// @Summary Restaura um usuário excluído
+// @Description Devolve o usuário à lista de usuários, com a mesma role global (ou a role padrão, se ela tiver sido
+// @Description removida). Os vínculos com organizações, grupos e concessões removidos na exclusão não são
+// @Description restaurados. Disponível apenas no escopo global.
+// @Tags users
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do usuário"
+// @Success 200 {object} models.AdminUserResponse "Usuário restaurado"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 403 {string} string "Disponível apenas no escopo global"
+// @Failure 404 {string} string "Usuário excluído não encontrado"
+// @Failure 409 {string} string "E-mail já cadastrado ou role padrão inexistente"
+// @Router /admin/users/{id}/restore [post]
+func RestoreUser(w http.ResponseWriter, r *http.Request) {
+	if !requireGlobalScope(w, r) {
+		return
+	}
+	user, ok := findDeletedUser(w, r)
+	if !ok {
+		return
+	}
+
+	// O e-mail pode ter sido cadastrado novamente enquanto o usuário estava na lixeira
+	var count int64
+	database.DB.Model(&models.User{}).Where("email = ?", user.Email).Count(&count)
+	if count > 0 {
+		http.Error(w, "E-mail já cadastrado por outro usuário", http.StatusConflict)
+		return
+	}
+
+	// A role pode ter sido removida enquanto o usuário estava na lixeira (DeleteRole só considera os usuários
+	// não excluídos); nesse caso ele volta com a role padrão
+	updates := map[string]interface{}{"deleted_at": nil}
+	if err := database.DB.First(&models.Role{}, user.RoleID).Error; err != nil {
+		defaultRole := settings.LoadSettings().RBAC.DefaultRole
+		var role models.Role
+		if err := database.DB.Where("name = ? AND organization_id IS NULL", defaultRole).First(&role).Error; err != nil {
+			http.Error(w, "A role do usuário foi removida e a role padrão não existe", http.StatusConflict)
+			return
+		}
+		updates["role_id"] = role.ID
+	}
+
+	// A verificação acima não impede um cadastro concorrente: o índice único decide
+	if err := database.DB.Unscoped().Model(&user).Updates(updates).Error; err != nil {
+		if utils.IsUniqueViolation(err) {
+			http.Error(w, "E-mail já cadastrado por outro usuário", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Erro ao restaurar usuário", http.StatusInternalServerError)
+		return
+	}
+	if err := database.DB.Preload("Role").First(&user, user.ID).Error; err != nil {
+		http.Error(w, "Erro ao restaurar usuário", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewAdminUserResponse(user))
+}
+
+// PurgeUser remove definitivamente um usuário da lixeira
+// @Summary Remove definitivamente um usuário excluído
+// @Description Apaga o usuário e os registros que dependem dele; não pode ser desfeito. Nas concessões de outros
+// @Description usuários, a referência a quem solicitou ou decidiu fica vazia. Apenas usuários já excluídos
+// @Description (DELETE /admin/users/{id}) são alcançados. Disponível apenas no escopo global.
+// @Tags users
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do usuário"
+// @Success 200 {object} map[string]string "Usuário removido definitivamente"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 403 {string} string "Disponível apenas no escopo global"
+// @Failure 404 {string} string "Usuário excluído não encontrado"
+// @Router /admin/users/deleted/{id} [delete]
+func PurgeUser(w http.ResponseWriter, r *http.Request) {
+	if !requireGlobalScope(w, r) {
+		return
+	}
+	user, ok := findDeletedUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return utils.PurgeUser(tx, user.ID)
+	})
+	if err != nil {
+		http.Error(w, "Erro ao remover usuário", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Usuário removido definitivamente"})
+}
+
+// requireGlobalScope recusa a requisição feita em uma organização: os usuários excluídos perdem os vínculos
+// e por isso não pertencem a nenhuma
+func requireGlobalScope(w http.ResponseWriter, r *http.Request) bool {
+	if callerOrg(r) != 0 {
+		http.Error(w, "Disponível apenas no escopo global", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// findDeletedUser carrega o usuário excluído informado na rota
+func findDeletedUser(w http.ResponseWriter, r *http.Request) (models.User, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return models.User{}, false
+	}
+
+	var user models.User
+	if err := database.DB.Unscoped().Where("deleted_at IS NOT NULL").First(&user, id).Error; err != nil {
+		http.Error(w, "Usuário excluído não encontrado", http.StatusNotFound)
+		return models.User{}, false
+	}
+	return user, true
+}
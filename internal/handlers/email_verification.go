@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/mailer"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/internal/utils"
+)
+
+// VerifyEmail confirma o e-mail do usuário
+// @Summary Confirma o e-mail
+// @Description Confirma o e-mail do usuário com o token assinado enviado no link de verificação
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param request body models.VerifyEmailRequest true "Token de verificação"
+// @Success 200 {object} map[string]string "E-mail verificado"
+// @Failure 400 {string} string "Token inválido ou expirado"
+// @Failure 500 {string} string "Erro ao verificar e-mail"
+// @Router /email/verify [post]
+func VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var request models.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Token == "" {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := utils.ParseEmailVerificationToken(request.Token)
+	if err != nil {
+		http.Error(w, "Token de verificação inválido ou expirado", http.StatusBadRequest)
+		return
+	}
+
+	// O token só vale para o e-mail para o qual foi emitido
+	var user models.User
+	if err := database.DB.First(&user, claims.UserID).Error; err != nil || !claims.EmailMatches(user.Email) {
+		http.Error(w, "Token de verificação inválido ou expirado", http.StatusBadRequest)
+		return
+	}
+
+	if user.VerifiedAt == nil {
+		now := time.Now()
+		if err := database.DB.Model(&user).Update("verified_at", now).Error; err != nil {
+			http.Error(w, "Erro ao verificar e-mail", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "E-mail verificado com sucesso"})
+}
+
+// ResendVerification reenvia o link de verificação de e-mail
+// @Summary Reenvia o link de verificação
+// @Description Reenvia o link de verificação para contas ainda não verificadas.
+// @Description A resposta é sempre a mesma, exista ou não uma conta pendente com o e-mail informado.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param request body models.ResendVerificationRequest true "E-mail da conta"
+// @Success 200 {object} map[string]string "Solicitação recebida"
+// @Failure 400 {string} string "Dados inválidos"
+// @Router /email/verify/resend [post]
+func ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var request models.ResendVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Email == "" {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Where("email = ? AND verified_at IS NULL", strings.TrimSpace(request.Email)).First(&user).Error; err == nil {
+		mailer.Async(func() {
+			if err := sendVerificationEmail(user); err != nil {
+				log.Printf("Erro ao reenviar e-mail de verificação para o usuário %d: %v", user.ID, err)
+			}
+		})
+	}
+
+	// A resposta não revela se o e-mail está cadastrado, nem pelo conteúdo nem pelo tempo: o envio é feito
+	// em segundo plano
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Se houver uma conta pendente de verificação com este e-mail, um novo link foi enviado",
+	})
+}
+
+// sendVerificationEmail envia o link assinado de verificação de e-mail ao usuário
+func sendVerificationEmail(user models.User) error {
+	token, err := utils.GenerateEmailVerificationToken(user)
+	if err != nil {
+		return err
+	}
+
+	config := settings.LoadSettings()
+	link := fmt.Sprintf("%s/email/verify?token=%s", config.App.URL, url.QueryEscape(token))
+
+	return mailer.Send(mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Confirme o seu e-mail",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"Confirme o seu e-mail no %s acessando o link abaixo em até %s:\n\n%s\n\n"+
+			"Se você não criou esta conta, ignore este e-mail.\n",
+			user.Name, config.App.Name, config.Auth.EmailVerificationTTL, link),
+	})
+}
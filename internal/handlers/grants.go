@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// RequestMyGrant solicita uma concessão temporária de role para o usuário autenticado
+// @Summary Solicita uma concessão temporária de role
+// @Description Solicita uma role por tempo limitado (por exemplo, acesso de plantão) na organização da sessão.
+// @Description A concessão só vale depois de aprovada por um administrador e entre starts_at e expires_at.
+// @Tags grants
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param request body models.GrantRequest true "Role e período"
+// @Success 201 {object} models.GrantResponse "Concessão solicitada"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /me/grants [post]
+func RequestMyGrant(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var request models.GrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+	request.UserID = claims.UserID
+
+	createGrant(w, r, claims, request)
+}
+
+// GetMyGrants lista as concessões temporárias do usuário autenticado
+// @Summary Lista as minhas concessões temporárias
+// @Tags grants
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {array} models.GrantResponse "Concessões"
+// @Failure 500 {string} string "Erro ao buscar concessões"
+// @Router /me/grants [get]
+func GetMyGrants(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var grants []models.RoleGrant
+	err := database.DB.Preload("Role").Where("user_id = ?", claims.UserID).Order("id DESC").Find(&grants).Error
+	if err != nil {
+		http.Error(w, "Erro ao buscar concessões", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewGrantResponses(grants))
+}
+
+// GetGrants lista as concessões temporárias
+// @Summary Lista as concessões temporárias
+// @Description Retorna as concessões, opcionalmente filtradas pela situação. Em uma organização, apenas as dela.
+// @Tags grants
+// @Security BearerAuth
+// @Produce  json
+// @Param status query string false "Situação (pending, approved, rejected ou revoked)"
+// @Success 200 {array} models.GrantResponse "Concessões"
+// @Failure 500 {string} string "Erro ao buscar concessões"
+// @Router /admin/grants [get]
+func GetGrants(w http.ResponseWriter, r *http.Request) {
+	query := grantsQuery(r).Preload("Role").Order("id DESC")
+	if status := r.URL.Query().Get("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var grants []models.RoleGrant
+	if err := query.Find(&grants).Error; err != nil {
+		http.Error(w, "Erro ao buscar concessões", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewGrantResponses(grants))
+}
+
+// CreateGrant solicita uma concessão temporária de role para um usuário
+// @Summary Solicita uma concessão temporária para um usuário
+// @Description A concessão fica pendente até ser aprovada por outro administrador.
+// @Tags grants
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param request body models.GrantRequest true "Usuário, role e período"
+// @Success 201 {object} models.GrantResponse "Concessão solicitada"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /admin/grants [post]
+func CreateGrant(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var request models.GrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	createGrant(w, r, claims, request)
+}
+
+// ApproveGrant aprova uma concessão pendente
+// @Summary Aprova uma concessão temporária
+// @Description A concessão passa a valer no período solicitado. Não pode ser aprovada por quem a solicitou nem pelo beneficiário
+// @Description e, em uma organização, só por quem possui as capacidades da role.
+// @Tags grants
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da concessão"
+// @Success 200 {object} models.GrantResponse "Concessão aprovada"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 403 {string} string "A concessão deve ser aprovada por outro administrador"
+// @Failure 404 {string} string "Concessão não encontrada"
+// @Failure 409 {string} string "A concessão não está pendente ou já expirou"
+// @Router /admin/grants/{id}/approve [post]
+func ApproveGrant(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	grant, ok := findGrant(w, r)
+	if !ok {
+		return
+	}
+	if (grant.RequestedByID != nil && *grant.RequestedByID == claims.UserID) || grant.UserID == claims.UserID {
+		http.Error(w, "A concessão deve ser aprovada por outro administrador", http.StatusForbidden)
+		return
+	}
+	if !grant.ExpiresAt.After(time.Now()) {
+		http.Error(w, "A concessão já expirou", http.StatusConflict)
+		return
+	}
+	// Aprovar equivale a atribuir a role: em uma organização, só quem possui as capacidades dela
+	if messages := roleBeyondCaller(r, grant.RoleID); len(messages) > 0 {
+		http.Error(w, "Acesso negado: "+messages[0], http.StatusForbidden)
+		return
+	}
+
+	decideGrant(w, claims, grant, models.GrantPending, models.GrantApproved)
+}
+
+// RejectGrant rejeita uma concessão pendente
+// @Summary Rejeita uma concessão temporária
+// @Tags grants
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da concessão"
+// @Success 200 {object} models.GrantResponse "Concessão rejeitada"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Concessão não encontrada"
+// @Failure 409 {string} string "A concessão não está pendente"
+// @Router /admin/grants/{id}/reject [post]
+func RejectGrant(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	grant, ok := findGrant(w, r)
+	if !ok {
+		return
+	}
+
+	decideGrant(w, claims, grant, models.GrantPending, models.GrantRejected)
+}
+
+// RevokeGrant revoga uma concessão aprovada antes de ela expirar
+// @Summary Revoga uma concessão temporária
+// @Description As capacidades recebidas pela concessão deixam de valer imediatamente.
+// @Tags grants
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da concessão"
+// @Success 200 {object} models.GrantResponse "Concessão revogada"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Concessão não encontrada"
+// @Failure 409 {string} string "A concessão não está aprovada"
+// @Router /admin/grants/{id}/revoke [post]
+func RevokeGrant(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	grant, ok := findGrant(w, r)
+	if !ok {
+		return
+	}
+
+	decideGrant(w, claims, grant, models.GrantApproved, models.GrantRevoked)
+}
+
+// createGrant valida e registra uma concessão pendente na organização da sessão de quem solicita
+func createGrant(w http.ResponseWriter, r *http.Request, claims *utils.Claims, request models.GrantRequest) {
+	now := time.Now()
+	grant := models.RoleGrant{
+		UserID:        request.UserID,
+		RoleID:        request.RoleID,
+		StartsAt:      now,
+		ExpiresAt:     request.ExpiresAt,
+		Reason:        request.Reason,
+		Status:        models.GrantPending,
+		RequestedByID: &claims.UserID,
+	}
+	if request.StartsAt != nil {
+		grant.StartsAt = *request.StartsAt
+	}
+	if organizationID := callerOrg(r); organizationID != 0 {
+		grant.OrganizationID = &organizationID
+	}
+
+	errs := validator.Errors{}
+	var user models.User
+	if err := usersQuery(r).First(&user, grant.UserID).Error; err != nil {
+		errs.Add("user_id", "Usuário não encontrado")
+	}
+	role, messages := loadRequestableRole(r, grant.RoleID)
+	errs.Add("role_id", messages...)
+	maxDuration := settings.LoadSettings().RBAC.GrantMaxDuration
+	switch {
+	case grant.ExpiresAt.IsZero():
+		errs.Add("expires_at", "A data de expiração é obrigatória")
+	case !grant.ExpiresAt.After(now):
+		errs.Add("expires_at", "A data de expiração deve estar no futuro")
+	case !grant.ExpiresAt.After(grant.StartsAt):
+		errs.Add("expires_at", "A data de expiração deve ser posterior ao início")
+	case grant.ExpiresAt.Sub(grant.StartsAt) > maxDuration:
+		errs.Add("expires_at", "A concessão não pode durar mais que "+maxDuration.String())
+	}
+	if len(grant.Reason) > 500 {
+		errs.Add("reason", "O motivo deve ter no máximo 500 caracteres")
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	if err := database.DB.Create(&grant).Error; err != nil {
+		http.Error(w, "Erro ao solicitar concessão", http.StatusInternalServerError)
+		return
+	}
+	grant.Role = *role
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.NewGrantResponse(grant))
+}
+
+// decideGrant move a concessão da situação esperada para a nova, registrando quem decidiu.
+// A condição na atualização impede que duas decisões simultâneas sejam aplicadas.
+func decideGrant(w http.ResponseWriter, claims *utils.Claims, grant *models.RoleGrant, from, to string) {
+	now := time.Now()
+	result := database.DB.Model(grant).Where("status = ?", from).Updates(map[string]interface{}{
+		"status":        to,
+		"decided_by_id": claims.UserID,
+		"decided_at":    now,
+	})
+	if result.Error != nil {
+		http.Error(w, "Erro ao atualizar concessão", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected == 0 {
+		message := "A concessão não está pendente"
+		if from == models.GrantApproved {
+			message = "A concessão não está aprovada"
+		}
+		http.Error(w, message, http.StatusConflict)
+		return
+	}
+	grant.Status = to
+	grant.DecidedByID = &claims.UserID
+	grant.DecidedAt = &now
+
+	// Aprovar ou revogar muda as capacidades do usuário imediatamente
+	if to == models.GrantApproved || to == models.GrantRevoked {
+		rbac.Invalidate()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewGrantResponse(*grant))
+}
+
+// grantsQuery inicia uma consulta de concessões restrita à organização de quem chama.
+// No escopo global todas as concessões são visíveis.
+func grantsQuery(r *http.Request) *gorm.DB {
+	if organizationID := callerOrg(r); organizationID != 0 {
+		return database.DB.Where("organization_id = ?", organizationID)
+	}
+	return database.DB
+}
+
+// findGrant carrega a concessão informada na rota, com a role. Em uma organização, as das demais são tratadas como inexistentes.
+func findGrant(w http.ResponseWriter, r *http.Request) (*models.RoleGrant, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return nil, false
+	}
+
+	var grant models.RoleGrant
+	if err := grantsQuery(r).Preload("Role").First(&grant, id).Error; err != nil {
+		http.Error(w, "Concessão não encontrada", http.StatusNotFound)
+		return nil, false
+	}
+	return &grant, true
+}
@@ -0,0 +1,426 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// GetGroups lista os grupos
+// @Summary Lista os grupos
+// @Description Retorna os grupos com as suas roles. Em uma organização, apenas os grupos dela.
+// @Tags groups
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {array} models.Group "Grupos"
+// @Failure 500 {string} string "Erro ao buscar grupos"
+// @Router /admin/groups [get]
+func GetGroups(w http.ResponseWriter, r *http.Request) {
+	var groups []models.Group
+	if err := groupsQuery(r).Preload("Roles").Order("id").Find(&groups).Error; err != nil {
+		http.Error(w, "Erro ao buscar grupos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+// GetGroup retorna um grupo pelo ID
+// @Summary Retorna um grupo pelo ID
+// @Tags groups
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do grupo"
+// @Success 200 {object} models.Group "Grupo encontrado"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Grupo não encontrado"
+// @Router /admin/groups/{id} [get]
+func GetGroup(w http.ResponseWriter, r *http.Request) {
+	group, ok := findGroup(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// CreateGroup cria um grupo
+// @Summary Cria um grupo
+// @Description Cria um grupo com as roles que os seus membros recebem, além da role direta de cada um.
+// @Description Em uma organização, o grupo pertence a ela e só recebe roles globais ou da organização.
+// @Tags groups
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param group body models.GroupRequest true "Dados do grupo"
+// @Success 201 {object} models.Group "Grupo criado"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 409 {string} string "Já existe um grupo com este nome"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /admin/groups [post]
+func CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var request models.GroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	group := models.Group{Name: strings.TrimSpace(request.Name)}
+	if organizationID := callerOrg(r); organizationID != 0 {
+		group.OrganizationID = &organizationID
+	}
+
+	errs := validator.Errors{}
+	if group.Name == "" {
+		errs.Add("name", "O nome é obrigatório")
+	}
+	roles, messages := loadGroupRoles(r, request.RoleIDs, groupOrg(group))
+	for _, message := range messages {
+		errs.Add("role_ids", message)
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+	if groupNameTaken(group, group.Name) {
+		http.Error(w, "Já existe um grupo com este nome", http.StatusConflict)
+		return
+	}
+
+	group.Roles = roles
+	if err := database.DB.Create(&group).Error; err != nil {
+		http.Error(w, "Erro ao criar grupo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(group)
+}
+
+// UpdateGroup altera o nome e as roles de um grupo
+// @Summary Atualiza um grupo
+// @Description Altera o nome do grupo e substitui as suas roles (quando role_ids é informado).
+// @Description As capacidades dos membros são recalculadas imediatamente.
+// @Tags groups
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID do grupo"
+// @Param group body models.GroupRequest true "Dados do grupo"
+// @Success 200 {object} models.Group "Grupo atualizado"
+// @Failure 400 {string} string "ID ou dados inválidos"
+// @Failure 404 {string} string "Grupo não encontrado"
+// @Failure 409 {string} string "Já existe um grupo com este nome ou último administrador"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /admin/groups/{id} [put]
+func UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	group, ok := findGroup(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.GroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	errs := validator.Errors{}
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		name = group.Name
+	}
+	var roles []models.Role
+	if request.RoleIDs != nil {
+		var messages []string
+		roles, messages = loadGroupRoles(r, request.RoleIDs, groupOrg(*group))
+		for _, message := range messages {
+			errs.Add("role_ids", message)
+		}
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+	if groupNameTaken(*group, name) {
+		http.Error(w, "Já existe um grupo com este nome", http.StatusConflict)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(group).Update("name", name).Error; err != nil {
+			return err
+		}
+		if request.RoleIDs == nil {
+			return nil
+		}
+		return utils.GuardLastAdmin(tx, groupOrg(*group), func() error {
+			return tx.Model(group).Association("Roles").Replace(roles)
+		})
+	})
+	if !writeRoleError(w, err, "Erro ao atualizar grupo") {
+		return
+	}
+	rbac.Invalidate()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// DeleteGroup remove um grupo
+// @Summary Remove um grupo
+// @Description Remove o grupo; os membros deixam de receber as suas roles, mas as contas são mantidas.
+// @Tags groups
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do grupo"
+// @Success 200 {object} map[string]string "Grupo removido"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Grupo não encontrado"
+// @Failure 409 {string} string "Último administrador"
+// @Router /admin/groups/{id} [delete]
+func DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	group, ok := findGroup(w, r)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return utils.GuardLastAdmin(tx, groupOrg(*group), func() error {
+			if err := tx.Model(group).Association("Roles").Clear(); err != nil {
+				return err
+			}
+			if err := tx.Model(group).Association("Users").Clear(); err != nil {
+				return err
+			}
+			return tx.Delete(group).Error
+		})
+	})
+	if !writeRoleError(w, err, "Erro ao remover grupo") {
+		return
+	}
+	rbac.Invalidate()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Grupo removido com sucesso"})
+}
+
+// GetGroupMembers lista os membros de um grupo
+// @Summary Lista os membros de um grupo
+// @Tags groups
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do grupo"
+// @Success 200 {array} models.AdminUserResponse "Membros"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Grupo não encontrado"
+// @Router /admin/groups/{id}/members [get]
+func GetGroupMembers(w http.ResponseWriter, r *http.Request) {
+	group, ok := findGroup(w, r)
+	if !ok {
+		return
+	}
+
+	users := []models.User{}
+	err := database.DB.Preload("Role").
+		Joins("JOIN group_users ON group_users.user_id = users.id").
+		Where("group_users.group_id = ?", group.ID).
+		Order("users.id").Find(&users).Error
+	if err != nil {
+		http.Error(w, "Erro ao buscar membros", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewAdminUserResponses(users))
+}
+
+// AddGroupMember adiciona um usuário ao grupo
+// @Summary Adiciona um membro ao grupo
+// @Description O usuário passa a receber as roles do grupo. Em grupos de uma organização, o usuário precisa ser membro dela.
+// @Description Como concede roles, exige manage:roles além de manage:groups.
+// @Tags groups
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do grupo"
+// @Param user_id path int true "ID do usuário"
+// @Success 200 {object} map[string]string "Membro adicionado"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Grupo ou usuário não encontrado"
+// @Failure 409 {string} string "O usuário não pertence à organização do grupo"
+// @Router /admin/groups/{id}/members/{user_id} [put]
+func AddGroupMember(w http.ResponseWriter, r *http.Request) {
+	group, ok := findGroup(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	// Em uma organização só os próprios membros são visíveis
+	var user models.User
+	if err := usersQuery(r).First(&user, userID).Error; err != nil {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+
+	if organizationID := groupOrg(*group); organizationID != 0 {
+		_, err := utils.FindMembership(user.ID, organizationID)
+		if errors.Is(err, utils.ErrNotMember) {
+			http.Error(w, "O usuário não pertence à organização do grupo", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao buscar vínculo com a organização", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := database.DB.Model(group).Association("Users").Append(&user); err != nil {
+		http.Error(w, "Erro ao adicionar membro", http.StatusInternalServerError)
+		return
+	}
+	rbac.Invalidate()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Membro adicionado com sucesso"})
+}
+
+// RemoveGroupMember remove um usuário do grupo
+// @Summary Remove um membro do grupo
+// @Description O usuário deixa de receber as roles do grupo imediatamente; a role direta é mantida.
+// @Description Exige manage:roles além de manage:groups. O último administrador não pode perder o acesso.
+// @Tags groups
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do grupo"
+// @Param user_id path int true "ID do usuário"
+// @Success 200 {object} map[string]string "Membro removido"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Grupo ou membro não encontrado"
+// @Failure 409 {string} string "Último administrador"
+// @Router /admin/groups/{id}/members/{user_id} [delete]
+func RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
+	group, ok := findGroup(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	// Quem administra apenas pelo grupo pode ser o último administrador
+	var removed int64
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return utils.GuardLastAdmin(tx, groupOrg(*group), func() error {
+			result := tx.Exec("DELETE FROM group_users WHERE group_id = ? AND user_id = ?", group.ID, userID)
+			removed = result.RowsAffected
+			return result.Error
+		})
+	})
+	if !writeRoleError(w, err, "Erro ao remover membro") {
+		return
+	}
+	if removed == 0 {
+		http.Error(w, "Membro não encontrado", http.StatusNotFound)
+		return
+	}
+	rbac.Invalidate()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Membro removido com sucesso"})
+}
+
+// groupsQuery inicia uma consulta de grupos restrita à organização de quem chama.
+// No escopo global todos os grupos são visíveis.
+func groupsQuery(r *http.Request) *gorm.DB {
+	if organizationID := callerOrg(r); organizationID != 0 {
+		return database.DB.Where("organization_id = ?", organizationID)
+	}
+	return database.DB
+}
+
+// findGroup carrega o grupo informado na rota, com as roles. Em uma organização, os grupos das demais são tratados como inexistentes.
+func findGroup(w http.ResponseWriter, r *http.Request) (*models.Group, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return nil, false
+	}
+
+	var group models.Group
+	if err := groupsQuery(r).Preload("Roles").First(&group, id).Error; err != nil {
+		http.Error(w, "Grupo não encontrado", http.StatusNotFound)
+		return nil, false
+	}
+	return &group, true
+}
+
+// groupOrg retorna a organização dona do grupo; 0 para grupos globais
+func groupOrg(group models.Group) uint {
+	if group.OrganizationID == nil {
+		return 0
+	}
+	return *group.OrganizationID
+}
+
+// loadGroupRoles carrega as roles pelos IDs, retornando mensagens para as inexistentes, para as que não
+// podem ser usadas na organização do grupo e para as que concedem mais do que quem chama possui
+func loadGroupRoles(r *http.Request, ids []uint, organizationID uint) ([]models.Role, []string) {
+	if len(ids) == 0 {
+		return []models.Role{}, nil
+	}
+
+	var roles []models.Role
+	if err := database.DB.Where("id IN ?", ids).Find(&roles).Error; err != nil {
+		return nil, []string{"Erro ao buscar roles"}
+	}
+
+	usable := make(map[uint]bool, len(roles))
+	for _, role := range roles {
+		usable[role.ID] = utils.RoleUsableIn(role, organizationID)
+	}
+	var messages []string
+	for _, id := range ids {
+		if !usable[id] {
+			messages = append(messages, "Role não encontrada: "+strconv.FormatUint(uint64(id), 10))
+			continue
+		}
+		messages = append(messages, roleBeyondCaller(r, id)...)
+	}
+	return roles, messages
+}
+
+// groupNameTaken indica se outro grupo do mesmo escopo (global ou da organização) já usa o nome informado
+func groupNameTaken(group models.Group, name string) bool {
+	query := database.DB.Model(&models.Group{}).Where("name = ? AND id <> ?", name, group.ID)
+	if organizationID := groupOrg(group); organizationID != 0 {
+		query = query.Where("organization_id = ?", organizationID)
+	} else {
+		query = query.Where("organization_id IS NULL")
+	}
+
+	var count int64
+	query.Count(&count)
+	return count > 0
+}
@@ -2,26 +2,42 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/jeffemart/Gotham/internal/database"
 	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/policy"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/settings"
 	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
-// Login autentica o usuário e gera um token JWT
+// dummyPasswordHash é comparado quando o e-mail não existe, para que o tempo de resposta não revele a conta
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("gotham-dummy-password"), bcrypt.DefaultCost)
+
+// Login autentica o usuário e gera um access token JWT e um refresh token
 // @Summary Login do usuário
-// @Description Autentica o usuário e gera um token JWT
+// @Description Autentica o usuário e gera um access token JWT de curta duração e um refresh token opaco.
+// @Description Se o MFA for exigido, retorna um desafio (mfa_token) a ser concluído em /login/mfa.
+// @Description Com organization_id a sessão é aberta na organização, com a role do usuário nela.
 // @Accept  json
 // @Produce  json
 // @Param loginRequest body models.LoginRequest true "Credenciais do usuário"
-// @Success 200 {object} map[string]string "Token gerado"
+// @Success 200 {object} models.TokenResponse "Tokens gerados"
+// @Success 202 {object} models.MFAChallengeResponse "MFA exigido"
 // @Failure 400 {string} string "Dados inválidos"
-// @Failure 401 {string} string "Usuário não encontrado ou senha incorreta"
+// @Failure 401 {string} string "Credenciais inválidas"
+// @Failure 403 {string} string "Conta inativa, e-mail não verificado ou usuário fora da organização"
+// @Failure 429 {string} string "Muitas tentativas de login"
 // @Router /login [post]
 func Login(w http.ResponseWriter, r *http.Request) {
 	var loginRequest models.LoginRequest
@@ -30,104 +46,348 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Conta ou IP bloqueados por excesso de falhas
+	ip := utils.ClientIP(r)
+	wait, err := utils.CheckLoginLock(loginRequest.Email, ip)
+	if err != nil {
+		http.Error(w, "Erro ao verificar bloqueio de login", http.StatusInternalServerError)
+		return
+	}
+	if wait > 0 {
+		writeLoginLocked(w, wait)
+		return
+	}
+
+	// Usuário inexistente e senha incorreta recebem a mesma resposta, para não revelar quais contas existem
 	var user models.User
 	result := database.DB.Where("email = ?", loginRequest.Email).Preload("Role.Permissions").First(&user)
-	if result.Error != nil {
-		http.Error(w, "Usuário não encontrado", http.StatusUnauthorized)
+	passwordHash := dummyPasswordHash
+	if result.Error == nil {
+		passwordHash = []byte(user.Password)
+	}
+
+	// Verifica a senha (também para usuários inexistentes, para igualar o tempo de resposta)
+	err = bcrypt.CompareHashAndPassword(passwordHash, []byte(loginRequest.Password))
+	if result.Error != nil || err != nil {
+		if err := utils.RegisterLoginFailure(loginRequest.Email, ip); err != nil {
+			log.Printf("Erro ao registrar falha de login: %v", err)
+		}
+		http.Error(w, "Credenciais inválidas", http.StatusUnauthorized)
 		return
 	}
 
-	// Verifica a senha
-	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginRequest.Password))
-	if err != nil {
-		http.Error(w, "Senha incorreta", http.StatusUnauthorized)
+	// Apenas contas ativas fazem login; a situação só é revelada a quem acertou a senha
+	if !user.Active() {
+		http.Error(w, utils.InactiveAccountMessage(user.Status), http.StatusForbidden)
+		return
+	}
+
+	// Contas não verificadas não podem fazer login quando a verificação é obrigatória
+	if settings.LoadSettings().Auth.RequireEmailVerification && user.VerifiedAt == nil {
+		http.Error(w, "E-mail não verificado", http.StatusForbidden)
+		return
+	}
+
+	// Em uma organização vale a role do usuário nela, inclusive para a exigência de MFA
+	if loginRequest.OrganizationID != 0 {
+		membership, err := utils.FindMembership(user.ID, loginRequest.OrganizationID)
+		if errors.Is(err, utils.ErrNotMember) {
+			http.Error(w, "Usuário não pertence à organização", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao buscar organização", http.StatusInternalServerError)
+			return
+		}
+		user.RoleID = membership.RoleID
+		user.Role = membership.Role
+	}
+
+	// Usuários com MFA habilitado (ou cuja role exige MFA) recebem um desafio em vez dos tokens
+	if utils.MFARequired(user) {
+		challenge, err := utils.CreateMFAChallenge(user.ID, loginRequest.OrganizationID)
+		if err != nil {
+			http.Error(w, "Erro ao gerar desafio de MFA", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(models.MFAChallengeResponse{
+			MFARequired:        true,
+			MFAToken:           challenge,
+			EnrollmentRequired: !user.MFAEnabled,
+		})
 		return
 	}
 
-	// Gera o token JWT com as permissões do papel do usuário
-	token, err := utils.GenerateTokenWithPermissions(user)
+	// As falhas da conta só são zeradas quando o login é concluído: com MFA, depois do segundo fator
+	if err := utils.ResetLoginFailures(loginRequest.Email); err != nil {
+		log.Printf("Erro ao zerar falhas de login: %v", err)
+	}
+
+	// Gera o access token com as permissões do papel do usuário e um refresh token de nova família
+	info := utils.SessionInfoFromRequest(r)
+	info.OrganizationID = loginRequest.OrganizationID
+	tokens, err := utils.IssueTokenPair(user, info)
 	if err != nil {
 		http.Error(w, "Erro ao gerar token", http.StatusInternalServerError)
 		return
 	}
 
-	// Retorna o token
-	response := map[string]string{"token": token}
+	// Retorna os tokens
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// writeLoginLocked responde ao login bloqueado por excesso de falhas, informando quando tentar novamente
+func writeLoginLocked(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+	http.Error(w, "Muitas tentativas de login. Tente novamente mais tarde", http.StatusTooManyRequests)
 }
 
-// RefreshToken gera um novo token JWT usando o refresh token
-// @Summary Gera um novo token JWT usando o refresh token
-// @Description Gera um novo token JWT quando fornecido um refresh token válido
+// RefreshToken troca um refresh token válido por um novo par de tokens
+// @Summary Renova os tokens usando o refresh token
+// @Description Consome o refresh token (rotação) e retorna um novo access token e um novo refresh token.
+// @Description A reapresentação de um refresh token já utilizado revoga toda a família de tokens.
 // @Accept  json
 // @Produce  json
 // @Param refreshRequest body models.RefreshRequest true "Refresh Token"
-// @Success 200 {object} map[string]string "Novo token gerado"
+// @Success 200 {object} models.TokenResponse "Novos tokens gerados"
 // @Failure 400 {string} string "Dados inválidos"
 // @Failure 401 {string} string "Refresh token inválido"
-// @Failure 404 {string} string "Usuário não encontrado"
+// @Failure 403 {string} string "Conta inativa"
 // @Router /refresh_token [post]
 func RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var refreshRequest models.RefreshRequest
-	if err := json.NewDecoder(r.Body).Decode(&refreshRequest); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&refreshRequest); err != nil || refreshRequest.RefreshToken == "" {
 		http.Error(w, "Dados inválidos", http.StatusBadRequest)
 		return
 	}
 
-	// Valida o refresh token
-	claims, err := utils.ValidateToken(refreshRequest.RefreshToken)
+	// Consome o refresh token; um token reutilizado revoga a família inteira
+	userID, family, err := utils.RotateRefreshToken(refreshRequest.RefreshToken)
+	if errors.Is(err, utils.ErrRefreshTokenReused) {
+		http.Error(w, "Refresh token reutilizado: sessão revogada", http.StatusUnauthorized)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Refresh token inválido", http.StatusUnauthorized)
 		return
 	}
 
-	// Gera um novo token usando o email da claim
 	var user models.User
-	result := database.DB.Where("email = ?", claims.Email).First(&user)
+	result := database.DB.First(&user, userID)
 	if result.Error != nil {
+		utils.RevokeSession(family)
 		http.Error(w, "Usuário não encontrado", http.StatusUnauthorized)
 		return
 	}
+	if !user.Active() {
+		utils.RevokeSession(family)
+		http.Error(w, utils.InactiveAccountMessage(user.Status), http.StatusForbidden)
+		return
+	}
 
-	token, err := utils.GenerateTokenWithPermissions(user)
+	tokens, err := utils.RefreshTokenPair(user, family)
+	if errors.Is(err, utils.ErrRefreshTokenInvalid) {
+		http.Error(w, "Refresh token inválido", http.StatusUnauthorized)
+		return
+	}
+	// O usuário foi removido da organização da sessão
+	if errors.Is(err, utils.ErrNotMember) {
+		utils.RevokeSession(family)
+		http.Error(w, "Usuário não pertence mais à organização", http.StatusUnauthorized)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Erro ao gerar novo token", http.StatusInternalServerError)
 		return
 	}
 
-	// Retorna o novo token
-	response := map[string]string{"token": token}
+	// Retorna os novos tokens
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// Logout revoga o token atual e a sessão a que ele pertence
+// @Summary Encerra a sessão atual
+// @Description Revoga o access token utilizado na requisição e a família de refresh tokens da sessão
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} map[string]string "Sessão encerrada"
+// @Failure 401 {string} string "Token inválido"
+// @Failure 500 {string} string "Erro ao encerrar sessão"
+// @Router /logout [post]
+func Logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+	tokenString, _ := r.Context().Value(utils.TokenKey).(string)
+
+	if err := utils.RevokeToken(tokenString); err != nil {
+		http.Error(w, "Erro ao encerrar sessão", http.StatusInternalServerError)
+		return
+	}
+	if claims.SessionID != "" {
+		if err := utils.RevokeSession(claims.SessionID); err != nil {
+			http.Error(w, "Erro ao encerrar sessão", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Sessão encerrada com sucesso"})
+}
+
+// LogoutAll revoga todas as sessões do usuário autenticado
+// @Summary Encerra todas as sessões do usuário
+// @Description Revoga o token atual e todas as sessões (refresh tokens e access tokens) do usuário autenticado
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} map[string]string "Sessões encerradas"
+// @Failure 401 {string} string "Token inválido"
+// @Failure 500 {string} string "Erro ao encerrar sessões"
+// @Router /logout/all [post]
+func LogoutAll(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+	tokenString, _ := r.Context().Value(utils.TokenKey).(string)
+
+	if err := utils.RevokeToken(tokenString); err != nil {
+		http.Error(w, "Erro ao encerrar sessões", http.StatusInternalServerError)
+		return
+	}
+	if err := utils.RevokeUserSessions(claims.UserID); err != nil {
+		http.Error(w, "Erro ao encerrar sessões", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Todas as sessões foram encerradas"})
+}
+
+// RevokeUserSessions revoga todas as sessões de um usuário
+// @Summary Revoga as sessões de um usuário
+// @Description Revoga todas as sessões (refresh tokens e access tokens) do usuário informado
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do usuário"
+// @Success 200 {object} map[string]string "Sessões revogadas"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Usuário não encontrado"
+// @Failure 500 {string} string "Erro ao revogar sessões"
+// @Router /admin/users/{id}/revoke-sessions [post]
+func RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := usersQuery(r).First(&user, id).Error; err != nil {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+
+	if err := utils.RevokeUserSessions(user.ID); err != nil {
+		http.Error(w, "Erro ao revogar sessões", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Sessões do usuário revogadas com sucesso"})
+}
+
+// JWKS publica as chaves públicas usadas na assinatura dos tokens
+// @Summary Chaves públicas de assinatura (JWKS)
+// @Description Retorna as chaves públicas (ativa e aposentadas) para que outros serviços verifiquem os tokens emitidos
+// @Produce  json
+// @Success 200 {object} keyring.JWKS "Conjunto de chaves públicas"
+// @Failure 500 {string} string "Erro ao carregar chaves"
+// @Router /.well-known/jwks.json [get]
+func JWKS(w http.ResponseWriter, r *http.Request) {
+	ring, err := utils.Keyring()
+	if err != nil {
+		http.Error(w, "Erro ao carregar chaves", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(ring.JWKS())
 }
 
 // CreateUser cria um novo usuário
 // @Summary Cria um novo usuário
-// @Description Cria um novo usuário no sistema com os dados fornecidos
+// @Description Cria um novo usuário no sistema com os dados fornecidos. O usuário recebe a role padrão (RBAC_DEFAULT_ROLE).
+// @Tags users
 // @Accept  json
 // @Produce  json
-// @Param user body models.User true "Dados do novo usuário"
-// @Success 201 {object} models.User "Usuário criado com sucesso"
+// @Param user body models.CreateUserRequest true "Dados do novo usuário"
+// @Success 201 {object} models.UserResponse "Usuário criado com sucesso"
 // @Failure 400 {string} string "Dados inválidos"
+// @Failure 409 {string} string "E-mail já cadastrado"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
 // @Failure 500 {string} string "Erro ao criar usuário"
 // @Router /users [post]
 func CreateUser(w http.ResponseWriter, r *http.Request) {
-	var user models.User
-	// Decodifica o corpo da requisição para o objeto user
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	var request models.CreateUserRequest
+	// Decodifica o corpo da requisição; campos desconhecidos (como role_id) são recusados
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
 		http.Error(w, "Dados inválidos", http.StatusBadRequest)
 		return
 	}
 
+	// Valida os campos antes de qualquer gravação
+	user := models.User{
+		Name:  strings.TrimSpace(request.Name),
+		Email: strings.TrimSpace(request.Email),
+	}
+	errs := validator.Errors{}
+	if user.Name == "" {
+		errs.Add("name", "O nome é obrigatório")
+	}
+	if !validator.EmailValidator(user.Email) {
+		errs.Add("email", "E-mail inválido")
+	}
+	errs.Add("password", utils.ValidatePassword(request.Password, user.Email, user.Name)...)
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	var count int64
+	database.DB.Model(&models.User{}).Where("email = ?", user.Email).Count(&count)
+	if count > 0 {
+		http.Error(w, "E-mail já cadastrado", http.StatusConflict)
+		return
+	}
+
+	// A role do cadastro público é sempre a padrão
+	var role models.Role
+	if err := database.DB.Where("name = ? AND organization_id IS NULL", settings.LoadSettings().RBAC.DefaultRole).First(&role).Error; err != nil {
+		log.Printf("Role padrão %q não encontrada: %v", settings.LoadSettings().RBAC.DefaultRole, err)
+		http.Error(w, "Erro ao criar usuário", http.StatusInternalServerError)
+		return
+	}
+	user.RoleID = role.ID
+
 	// Criptografa a senha do usuário com bcrypt antes de salvar no banco
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
 		http.Error(w, "Erro ao criptografar senha", http.StatusInternalServerError)
 		return
 	}
-
-	// Substitui a senha do usuário pela versão criptografada
 	user.Password = string(hashedPassword)
 
 	// Salva o usuário no banco de dados
@@ -137,18 +397,27 @@ func CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Envia o link de verificação; uma falha no envio não impede o cadastro
+	if err := sendVerificationEmail(user); err != nil {
+		log.Printf("Erro ao enviar e-mail de verificação para o usuário %d: %v", user.ID, err)
+	}
+
 	// Retorna o usuário criado com status 201 (Created)
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(user)
+	json.NewEncoder(w).Encode(models.NewUserResponse(user))
 }
 
 // GetUser retorna um usuário pelo ID
 // @Summary Retorna um usuário pelo ID
-// @Description Obtém um usuário específico com base no ID fornecido
+// @Description Obtém um usuário específico com base no ID fornecido.
+// @Description Exige read:user:any para qualquer usuário ou read:user:own para o próprio perfil; em uma organização, apenas os seus membros.
+// @Security BearerAuth
 // @Produce  json
 // @Param id path int true "ID do usuário"
-// @Success 200 {object} models.User "Usuário encontrado"
+// @Success 200 {object} models.AdminUserResponse "Usuário encontrado (visão pública, models.UserResponse, com read:user:own)"
 // @Failure 400 {string} string "ID inválido"
+// @Failure 403 {string} string "Acesso negado"
 // @Failure 404 {string} string "Usuário não encontrado"
 // @Router /users/{id} [get]
 func GetUser(w http.ResponseWriter, r *http.Request) {
@@ -159,25 +428,63 @@ func GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+	if decision := policy.Can(claims, models.CapabilityReadUser, uint(id)); !decision.Allowed {
+		http.Error(w, "Acesso negado: "+decision.Reason, http.StatusForbidden)
+		return
+	}
+
 	var user models.User
-	result := database.DB.First(&user, id)
+	result := usersQuery(r).Preload("Role").First(&user, id)
 	if result.Error != nil {
 		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
 		return
 	}
 
-	json.NewEncoder(w).Encode(user)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(userView(claims, models.CapabilityReadUser, user))
+}
+
+// userView escolhe a visão do usuário na resposta: a administrativa para quem tem o escopo any da capacidade
+// e a pública para quem só alcança o próprio registro
+func userView(claims *utils.Claims, capability string, user models.User) interface{} {
+	if decision := policy.Can(claims, capability, 0); decision.Allowed {
+		return models.NewAdminUserResponse(user)
+	}
+	return models.NewUserResponse(user)
 }
 
-// GetUsers retorna todos os usuários cadastrados com paginação
+// GetUsers retorna os usuários cadastrados com busca, filtros, ordenação e paginação
 // @Summary      Lista todos os usuários
-// @Description  Retorna uma lista paginada de todos os usuários
+// @Description  Retorna uma lista paginada dos usuários; em uma organização, apenas os seus membros.
+// @Description  A busca (q) procura o trecho no nome ou no e-mail, sem diferenciar maiúsculas. Em uma organização,
+// @Description  role_id filtra pela role do vínculo com ela. created_from e created_to aceitam RFC 3339 ou AAAA-MM-DD;
+// @Description  uma data sem horário em created_to inclui o dia inteiro.
+// @Description  Com pagination=cursor, a listagem é paginada por cursor: as páginas seguintes e anteriores são pedidas
+// @Description  com o next_cursor ou o prev_cursor da resposta (com os mesmos filtros e ordenação), sem OFFSET.
+// @Description  Nesse modo não é possível ordenar por verified_at. count=false dispensa a contagem do total.
 // @Tags         users
+// @Security     BearerAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query int false "Número da página" default(1)
-// @Param        limit query int false "Itens por página" default(10)
-// @Success      200  {object}  models.PaginatedResponse
+// @Param        limit query int false "Itens por página (no máximo 100)" default(10)
+// @Param        pagination query string false "Modo de paginação" Enums(page, cursor) default(page)
+// @Param        cursor query string false "Cursor retornado em next_cursor ou prev_cursor"
+// @Param        count query bool false "Contar o total de usuários" default(true)
+// @Param        q query string false "Trecho do nome ou do e-mail"
+// @Param        role_id query int false "ID da role"
+// @Param        status query string false "Situação da conta" Enums(active, pending, suspended, banned)
+// @Param        verified query bool false "E-mail verificado"
+// @Param        created_from query string false "Criados a partir de"
+// @Param        created_to query string false "Criados até"
+// @Param        sort query string false "Campos de ordenação separados por vírgula; '-' para decrescente (id, name, email, created_at, updated_at, verified_at)" default(id)
+// @Success      200  {object}  models.PaginatedResponse{data=[]models.AdminUserResponse}
+// @Failure 422 {object} models.ValidationErrorResponse "Parâmetros inválidos"
 // @Failure 500 {object} models.ErrorResponse "Erro ao contar usuários"
 // @Router       /users [get]
 func GetUsers(w http.ResponseWriter, r *http.Request) {
@@ -201,36 +508,59 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 			limit = 10
 		}
 	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
 
-	// Calcula o deslocamento (offset) baseado na página e no limite
-	offset := (page - 1) * limit
-
-	// Contagem total de usuários
-	var totalCount int64
-	if err := database.DB.Model(&models.User{}).Count(&totalCount).Error; err != nil {
-		http.Error(w, "Erro ao contar usuários", http.StatusInternalServerError)
+	listing, errs := parseUserListing(r)
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
 		return
 	}
 
-	// Consultar usuários com limite e offset para paginação
-	var users []models.User
-	if err := database.DB.Limit(limit).Offset(offset).Find(&users).Error; err != nil {
-		http.Error(w, "Erro ao buscar usuários", http.StatusInternalServerError)
-		return
+	response := models.PaginatedResponse{
+		Status:  http.StatusOK,
+		Message: "Usuários encontrados",
+		Limit:   limit,
 	}
 
-	// Calcula o número total de páginas
-	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+	// Contagem total de usuários, que pode ser dispensada em tabelas grandes
+	if r.URL.Query().Get("count") != "false" {
+		var totalCount int64
+		if err := usersQuery(r).Model(&models.User{}).Scopes(listing.filters).Count(&totalCount).Error; err != nil {
+			http.Error(w, "Erro ao contar usuários", http.StatusInternalServerError)
+			return
+		}
+		total := int(totalCount)
+		response.TotalCount = &total
+		if !listing.cursorMode {
+			// Calcula o número total de páginas
+			totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+			response.TotalPages = &totalPages
+		}
+	}
 
-	// Monta a resposta paginada
-	response := models.PaginatedResponse{
-		Status:      http.StatusOK,
-		Message:     "Usuários encontrados",
-		Data:        users,
-		TotalCount:  int(totalCount),
-		TotalPages:  totalPages,
-		CurrentPage: page,
-		Limit:       limit,
+	if listing.cursorMode {
+		users, next, prev, err := listUsersByCursor(r, listing, limit)
+		if err != nil {
+			http.Error(w, "Erro ao buscar usuários", http.StatusInternalServerError)
+			return
+		}
+		response.Data = models.NewAdminUserResponses(users)
+		response.NextCursor = next
+		response.PrevCursor = prev
+	} else {
+		// Calcula o deslocamento (offset) baseado na página e no limite
+		offset := (page - 1) * limit
+
+		// Consultar usuários com limite e offset para paginação
+		var users []models.User
+		if err := usersQuery(r).Scopes(listing.filters).Preload("Role").Order(utils.OrderClause(listing.sort)).Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+			http.Error(w, "Erro ao buscar usuários", http.StatusInternalServerError)
+			return
+		}
+		response.Data = models.NewAdminUserResponses(users)
+		response.CurrentPage = page
 	}
 
 	// Retorna a resposta como JSON
@@ -240,16 +570,25 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 
 // UpdateUser atualiza as informações de um usuário
 // @Summary Atualiza as informações de um usuário
-// @Description Atualiza os dados de um usuário com base no ID fornecido
+// @Description Atualiza os dados de um usuário com base no ID fornecido.
+// @Description Exige update:user:any para qualquer usuário ou update:user:own para o próprio perfil; trocar a role exige manage:roles.
+// @Description A própria senha não é alterada aqui (use POST /me/password); definir a senha de outro usuário revoga as sessões dele.
+// @Description Em uma organização, e-mail e senha só são alterados em contas que não pertencem a outras organizações
+// @Description nem administram o escopo global.
+// @Tags users
 // @Accept  json
 // @Security BearerAuth
 // @Produce  json
 // @Param id path int true "ID do usuário"
-// @Param user body models.User true "Dados do usuário a serem atualizados"
-// @Success 200 {object} models.User "Usuário atualizado com sucesso"
+// @Param user body models.UpdateUserRequest true "Dados do usuário a serem atualizados"
+// @Success 200 {object} models.AdminUserResponse "Usuário atualizado (visão pública, models.UserResponse, com update:user:own)"
 // @Failure 400 {string} string "ID ou dados inválidos"
+// @Failure 403 {string} string "Acesso negado"
 // @Failure 404 {string} string "Usuário não encontrado"
+// @Failure 409 {string} string "Último administrador ou conta usada fora da organização"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
 // @Failure 500 {string} string "Erro ao atualizar usuário"
+// @Router /users/{id} [put]
 // @Router /admin/users/{id} [put]
 func UpdateUser(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -259,27 +598,89 @@ func UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var user models.User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	var user models.UpdateUserRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&user); err != nil {
 		http.Error(w, "Dados inválidos", http.StatusBadRequest)
 		return
 	}
 
 	// Carrega o usuário do banco de dados
 	var existingUser models.User
-	result := database.DB.First(&existingUser, id)
+	result := usersQuery(r).Preload("Role").First(&existingUser, id)
 	if result.Error != nil {
 		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
 		return
 	}
 
+	// update:user:any permite editar qualquer usuário; update:user:own, apenas o próprio perfil
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+	if decision := policy.Can(claims, models.CapabilityUpdateUser, existingUser.ID); !decision.Allowed {
+		http.Error(w, "Acesso negado: "+decision.Reason, http.StatusForbidden)
+		return
+	}
+
 	// Atualiza os campos fornecidos na requisição, se não forem os valores padrão (zero)
+	user.Email = strings.TrimSpace(user.Email)
+	user.Name = strings.TrimSpace(user.Name)
+
+	// E-mail e senha valem para a conta inteira: uma organização só os altera na conta de outro usuário se a
+	// conta for apenas dela
+	if existingUser.ID != claims.UserID && ((user.Email != "" && user.Email != existingUser.Email) || user.Password != "") {
+		outside, err := accountOutsideCaller(r, existingUser)
+		if err != nil {
+			http.Error(w, "Erro ao atualizar usuário", http.StatusInternalServerError)
+			return
+		}
+		if outside {
+			http.Error(w, "O usuário pertence a outras organizações ou administra o escopo global; e-mail e senha só podem ser alterados no escopo global", http.StatusConflict)
+			return
+		}
+	}
+	errs := validator.Errors{}
+	emailChanged := false
 	if user.Email != "" && user.Email != existingUser.Email {
+		if !validator.EmailValidator(user.Email) {
+			errs.Add("email", "E-mail inválido")
+		}
 		existingUser.Email = user.Email
+		// Um novo e-mail precisa ser verificado novamente
+		existingUser.VerifiedAt = nil
+		emailChanged = true
 	}
 	if user.Name != "" && user.Name != existingUser.Name {
 		existingUser.Name = user.Name
 	}
+	if user.Password != "" {
+		if existingUser.ID == claims.UserID {
+			// A própria senha só é trocada com a confirmação da senha atual
+			errs.Add("password", "Para alterar a própria senha use POST /me/password")
+		} else {
+			// A senha é comparada com o e-mail e o nome já atualizados
+			errs.Add("password", utils.ValidatePassword(user.Password, existingUser.Email, existingUser.Name)...)
+		}
+	}
+	var newRole *models.Role
+	if user.RoleID != 0 && user.RoleID != existingUser.RoleID {
+		// Trocar a role exige manage:roles, inclusive no próprio perfil
+		if decision := policy.Can(claims, models.CapabilityManageRoles, 0); !decision.Allowed {
+			http.Error(w, "Acesso negado: "+decision.Reason, http.StatusForbidden)
+			return
+		}
+
+		var messages []string
+		newRole, messages = loadAssignableRole(r, user.RoleID)
+		errs.Add("role_id", messages...)
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
 	if user.Password != "" { // Criptografa a senha se fornecida
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 		if err != nil {
@@ -288,30 +689,54 @@ func UpdateUser(w http.ResponseWriter, r *http.Request) {
 		}
 		existingUser.Password = string(hashedPassword)
 	}
-	if user.RoleID != 0 && user.RoleID != existingUser.RoleID {
-		existingUser.RoleID = user.RoleID
-	}
 
-	// Atualiza os campos no banco de dados
+	// Atualiza os campos no banco de dados; a troca de role passa pela proteção do último administrador
 	existingUser.UpdatedAt = time.Now()
-	result = database.DB.Save(&existingUser)
-	if result.Error != nil {
-		http.Error(w, "Erro ao atualizar usuário", http.StatusInternalServerError)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Role").Save(&existingUser).Error; err != nil {
+			return err
+		}
+		if newRole != nil {
+			return assignRole(tx, &existingUser, *newRole, callerOrg(r))
+		}
+		return nil
+	})
+	if !writeRoleError(w, err, "Erro ao atualizar usuário") {
 		return
 	}
 
+	if emailChanged {
+		if err := sendVerificationEmail(existingUser); err != nil {
+			log.Printf("Erro ao enviar e-mail de verificação para o usuário %d: %v", existingUser.ID, err)
+		}
+	}
+
+	// Uma senha definida por outra pessoa encerra as sessões abertas com a senha anterior
+	if user.Password != "" {
+		if err := utils.RevokeUserSessions(existingUser.ID); err != nil {
+			log.Printf("Erro ao revogar as sessões do usuário %d: %v", existingUser.ID, err)
+		}
+	}
+
 	// Retorna o usuário atualizado
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(existingUser)
+	json.NewEncoder(w).Encode(userView(claims, models.CapabilityUpdateUser, existingUser))
 }
 
 // DeleteUser remove um usuário pelo ID
 // @Summary Remove um usuário pelo ID
-// @Description Exclui um usuário com base no ID fornecido
+// @Description Exclui um usuário com base no ID fornecido. O usuário vai para a lixeira (GET /admin/users/deleted),
+// @Description perde os vínculos com organizações, grupos e concessões e tem as sessões revogadas; pode ser restaurado
+// @Description até a remoção definitiva, manual ou após USERS_DELETED_RETENTION.
+// @Description Em uma organização, apenas usuários que não pertencem a outras organizações nem administram o escopo
+// @Description global podem ser excluídos.
 // @Security BearerAuth
 // @Param id path int true "ID do usuário"
 // @Success 200 {object} map[string]string "Usuário excluído com sucesso"
 // @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Usuário não encontrado"
+// @Failure 409 {string} string "Último administrador ou membro de outras organizações"
 // @Failure 500 {string} string "Erro ao excluir usuário"
 // @Router /admin/users/{id} [delete]
 func DeleteUser(w http.ResponseWriter, r *http.Request) {
@@ -322,11 +747,48 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := database.DB.Delete(&models.User{}, id)
-	if result.Error != nil {
+	var user models.User
+	if err := usersQuery(r).First(&user, id).Error; err != nil {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+
+	// Uma organização não exclui a conta de quem também é membro de outras ou administra o escopo global
+	organizationID := callerOrg(r)
+	outside, err := accountOutsideCaller(r, user)
+	if err != nil {
 		http.Error(w, "Erro ao excluir usuário", http.StatusInternalServerError)
 		return
 	}
+	if outside {
+		http.Error(w, "O usuário pertence a outras organizações ou administra o escopo global; remova-o apenas desta organização", http.StatusConflict)
+		return
+	}
+
+	// O último administrador não pode ser excluído
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return utils.GuardLastAdmin(tx, organizationID, func() error {
+			if err := tx.Where("user_id = ?", user.ID).Delete(&models.Membership{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM group_users WHERE user_id = ?", user.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ?", user.ID).Delete(&models.RoleGrant{}).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&user).Error
+		})
+	})
+	if !writeRoleError(w, err, "Erro ao excluir usuário") {
+		return
+	}
+	rbac.Invalidate()
+
+	// Os tokens já emitidos deixam de valer
+	if err := utils.RevokeUserSessions(user.ID); err != nil {
+		log.Printf("Erro ao revogar as sessões do usuário excluído %d: %v", user.ID, err)
+	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"message": "Usuário excluído com sucesso"})
@@ -334,19 +796,61 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 // GetTasks retorna uma lista de tarefas fictícias
 // @Summary Retorna uma lista de tarefas
-// @Description Obtém uma lista de tarefas fictícias para a demonstração
+// @Description Obtém uma lista de tarefas fictícias para a demonstração.
+// @Description Com view:tasks:any retorna todas as tarefas; com view:tasks:own, apenas as do usuário autenticado.
 // @Security BearerAuth
 // @Produce  json
 // @Success 200 {array} map[string]string "Lista de tarefas"
+// @Failure 403 {string} string "Acesso negado"
 // @Router /protected/tasks [get]
 func GetTasks(w http.ResponseWriter, r *http.Request) {
-	// Lista fictícia de tarefas
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	// Lista fictícia de tarefas; cada tarefa pertence a um usuário
 	tasks := []map[string]string{
-		{"id": "1", "task": "Finalizar relatório", "status": "Pendente"},
-		{"id": "2", "task": "Enviar e-mail para cliente", "status": "Concluído"},
-		{"id": "3", "task": "Atualizar sistema", "status": "Em progresso"},
+		{"id": "1", "task": "Finalizar relatório", "status": "Pendente", "owner_id": "1"},
+		{"id": "2", "task": "Enviar e-mail para cliente", "status": "Concluído", "owner_id": "1"},
+		{"id": "3", "task": "Atualizar sistema", "status": "Em progresso", "owner_id": "2"},
+	}
+
+	// Com view:tasks:any todas as tarefas são retornadas; com view:tasks:own, apenas as do usuário
+	role, err := utils.ResolveClaims(claims)
+	if err != nil {
+		http.Error(w, "Role não encontrada", http.StatusForbidden)
+		return
+	}
+	if !rbac.Allowed(role.Capabilities, models.CapabilityViewTasks+":"+policy.ScopeAny) &&
+		!rbac.Allowed(role.Capabilities, models.CapabilityViewOwnTasks) {
+		http.Error(w, "Acesso negado: capacidades insuficientes", http.StatusForbidden)
+		return
+	}
+
+	visible := []map[string]string{}
+	for _, task := range tasks {
+		ownerID, _ := strconv.ParseUint(task["owner_id"], 10, 64)
+		if policy.Decide(role.Capabilities, claims.UserID, models.CapabilityViewTasks, uint(ownerID)).Allowed {
+			visible = append(visible, task)
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tasks)
+	json.NewEncoder(w).Encode(visible)
+}
+
+// currentUser carrega o usuário autenticado (com a role) a partir das claims do contexto
+func currentUser(r *http.Request) (*models.User, bool) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		return nil, false
+	}
+
+	var user models.User
+	if err := database.DB.Preload("Role").First(&user, claims.UserID).Error; err != nil {
+		return nil, false
+	}
+	return &user, true
 }
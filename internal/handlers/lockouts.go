@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/utils"
+)
+
+// GetUserLockout retorna o estado do bloqueio de login de um usuário
+// @Summary Consulta o bloqueio de login de um usuário
+// @Description Retorna o número de falhas de login na janela atual e se a conta está bloqueada
+// @Tags lockouts
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do usuário"
+// @Success 200 {object} models.LockoutStatus "Estado do bloqueio"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Usuário não encontrado"
+// @Failure 500 {string} string "Erro ao buscar bloqueio"
+// @Router /admin/users/{id}/lockout [get]
+func GetUserLockout(w http.ResponseWriter, r *http.Request) {
+	user, ok := lockoutUser(w, r)
+	if !ok {
+		return
+	}
+
+	writeLockout(w, utils.LockoutScopeAccount, user.Email)
+}
+
+// ClearUserLockout remove o bloqueio de login de um usuário
+// @Summary Remove o bloqueio de login de um usuário
+// @Description Zera as falhas de login da conta e remove o bloqueio, se houver
+// @Tags lockouts
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do usuário"
+// @Success 200 {object} map[string]string "Bloqueio removido"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Usuário não encontrado"
+// @Failure 500 {string} string "Erro ao remover bloqueio"
+// @Router /admin/users/{id}/lockout [delete]
+func ClearUserLockout(w http.ResponseWriter, r *http.Request) {
+	user, ok := lockoutUser(w, r)
+	if !ok {
+		return
+	}
+
+	clearLockout(w, utils.LockoutScopeAccount, user.Email)
+}
+
+// GetIPLockout retorna o estado do bloqueio de login de um IP
+// @Summary Consulta o bloqueio de login de um IP
+// @Description Retorna o número de falhas de login do IP na janela atual e se ele está bloqueado
+// @Tags lockouts
+// @Security BearerAuth
+// @Produce  json
+// @Param ip path string true "Endereço IP"
+// @Success 200 {object} models.LockoutStatus "Estado do bloqueio"
+// @Failure 400 {string} string "IP inválido"
+// @Failure 500 {string} string "Erro ao buscar bloqueio"
+// @Router /admin/lockouts/ip/{ip} [get]
+func GetIPLockout(w http.ResponseWriter, r *http.Request) {
+	ip, ok := lockoutIP(w, r)
+	if !ok {
+		return
+	}
+
+	writeLockout(w, utils.LockoutScopeIP, ip)
+}
+
+// ClearIPLockout remove o bloqueio de login de um IP
+// @Summary Remove o bloqueio de login de um IP
+// @Description Zera as falhas de login do IP e remove o bloqueio, se houver
+// @Tags lockouts
+// @Security BearerAuth
+// @Produce  json
+// @Param ip path string true "Endereço IP"
+// @Success 200 {object} map[string]string "Bloqueio removido"
+// @Failure 400 {string} string "IP inválido"
+// @Failure 500 {string} string "Erro ao remover bloqueio"
+// @Router /admin/lockouts/ip/{ip} [delete]
+func ClearIPLockout(w http.ResponseWriter, r *http.Request) {
+	ip, ok := lockoutIP(w, r)
+	if !ok {
+		return
+	}
+
+	clearLockout(w, utils.LockoutScopeIP, ip)
+}
+
+// lockoutUser carrega o usuário informado na rota, respondendo com erro se ele não existir
+func lockoutUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return nil, false
+	}
+
+	var user models.User
+	if err := usersQuery(r).First(&user, id).Error; err != nil {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return nil, false
+	}
+	return &user, true
+}
+
+// lockoutIP valida o IP informado na rota e o retorna na forma canônica
+func lockoutIP(w http.ResponseWriter, r *http.Request) (string, bool) {
+	ip := net.ParseIP(mux.Vars(r)["ip"])
+	if ip == nil {
+		http.Error(w, "IP inválido", http.StatusBadRequest)
+		return "", false
+	}
+	return ip.String(), true
+}
+
+func writeLockout(w http.ResponseWriter, scope, target string) {
+	status, err := utils.GetLockout(scope, target)
+	if err != nil {
+		http.Error(w, "Erro ao buscar bloqueio", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+func clearLockout(w http.ResponseWriter, scope, target string) {
+	if err := utils.ClearLockout(scope, target); err != nil {
+		http.Error(w, "Erro ao remover bloqueio", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Bloqueio removido com sucesso"})
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// GetMe retorna o perfil do usuário autenticado
+// @Summary Retorna o meu perfil
+// @Description Retorna o usuário autenticado, com a sua role. Não exige nenhuma capacidade.
+// @Tags me
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} models.AdminUserResponse "Usuário autenticado"
+// @Failure 500 {string} string "Erro ao obter informações do usuário"
+// @Router /me [get]
+func GetMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewAdminUserResponse(*user))
+}
+
+// UpdateMe altera o nome ou o e-mail do usuário autenticado
+// @Summary Altera o meu perfil
+// @Description Altera apenas os campos informados. Um novo e-mail precisa ser verificado novamente.
+// @Description Campos fora do perfil (como role_id) são recusados: a role só é trocada por quem tem manage:roles.
+// @Tags me
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param request body models.ProfileUpdateRequest true "Campos do perfil"
+// @Success 200 {object} models.AdminUserResponse "Perfil atualizado"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 409 {string} string "E-mail já cadastrado"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /me [patch]
+func UpdateMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var request models.ProfileUpdateRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	errs := validator.Errors{}
+	updates := map[string]interface{}{}
+	if request.Name != nil {
+		name := strings.TrimSpace(*request.Name)
+		if name == "" {
+			errs.Add("name", "O nome é obrigatório")
+		} else if name != user.Name {
+			updates["name"] = name
+		}
+	}
+	emailChanged := false
+	if request.Email != nil {
+		email := strings.TrimSpace(*request.Email)
+		if !validator.EmailValidator(email) {
+			errs.Add("email", "E-mail inválido")
+		} else if email != user.Email {
+			// Um novo e-mail precisa ser verificado novamente
+			updates["email"] = email
+			updates["verified_at"] = nil
+			emailChanged = true
+		}
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	if emailChanged {
+		var count int64
+		database.DB.Model(&models.User{}).Where("email = ? AND id <> ?", updates["email"], user.ID).Count(&count)
+		if count > 0 {
+			http.Error(w, "E-mail já cadastrado", http.StatusConflict)
+			return
+		}
+	}
+
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now()
+		if err := database.DB.Model(user).Updates(updates).Error; err != nil {
+			http.Error(w, "Erro ao atualizar perfil", http.StatusInternalServerError)
+			return
+		}
+		if name, ok := updates["name"].(string); ok {
+			user.Name = name
+		}
+		if emailChanged {
+			user.Email = updates["email"].(string)
+			user.VerifiedAt = nil
+		}
+	}
+
+	if emailChanged {
+		if err := sendVerificationEmail(*user); err != nil {
+			log.Printf("Erro ao enviar e-mail de verificação para o usuário %d: %v", user.ID, err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewAdminUserResponse(*user))
+}
+
+// ChangeMyPassword troca a senha do usuário autenticado
+// @Summary Troca a minha senha
+// @Description Exige a senha atual; as tentativas erradas contam para o bloqueio de login da conta.
+// @Description As demais sessões do usuário são revogadas; a sessão atual é mantida.
+// @Tags me
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param request body models.ChangePasswordRequest true "Senha atual e nova senha"
+// @Success 200 {object} map[string]string "Senha alterada"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 401 {string} string "Senha atual incorreta"
+// @Failure 422 {object} models.ValidationErrorResponse "Senha fora da política"
+// @Failure 429 {string} string "Muitas tentativas"
+// @Router /me/password [post]
+func ChangeMyPassword(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+	user, ok := currentUser(r)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var request models.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.CurrentPassword == "" {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	// A senha atual é protegida pelo mesmo bloqueio do login, para que um token roubado não permita adivinhá-la
+	ip := utils.ClientIP(r)
+	wait, err := utils.CheckLoginLock(user.Email, ip)
+	if err != nil {
+		http.Error(w, "Erro ao verificar bloqueio de login", http.StatusInternalServerError)
+		return
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+		http.Error(w, "Muitas tentativas. Tente novamente mais tarde", http.StatusTooManyRequests)
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.CurrentPassword)); err != nil {
+		if err := utils.RegisterLoginFailure(user.Email, ip); err != nil {
+			log.Printf("Erro ao registrar falha de login: %v", err)
+		}
+		http.Error(w, "Senha atual incorreta", http.StatusUnauthorized)
+		return
+	}
+	if err := utils.ResetLoginFailures(user.Email); err != nil {
+		log.Printf("Erro ao zerar falhas de login: %v", err)
+	}
+
+	errs := validator.Errors{}
+	errs.Add("new_password", utils.ValidatePassword(request.NewPassword, user.Email, user.Name)...)
+	if request.NewPassword == request.CurrentPassword {
+		errs.Add("new_password", "A nova senha deve ser diferente da atual")
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Erro ao criptografar senha", http.StatusInternalServerError)
+		return
+	}
+	if err := database.DB.Model(user).Update("password", string(hashedPassword)).Error; err != nil {
+		http.Error(w, "Erro ao alterar senha", http.StatusInternalServerError)
+		return
+	}
+
+	// As sessões abertas com a senha antiga em outros dispositivos deixam de valer
+	sessions, err := utils.ListUserSessions(user.ID)
+	if err != nil {
+		log.Printf("Erro ao listar sessões do usuário %d após troca de senha: %v", user.ID, err)
+	}
+	for _, session := range sessions {
+		if session.ID == claims.SessionID {
+			continue
+		}
+		if err := utils.RevokeSession(session.ID); err != nil {
+			log.Printf("Erro ao revogar a sessão %s após troca de senha: %v", session.ID, err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Senha alterada com sucesso"})
+}
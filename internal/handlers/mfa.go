@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/utils"
+)
+
+// LoginMFA conclui o login em duas etapas
+// @Summary Conclui o login com MFA
+// @Description Valida o código TOTP (ou um código de recuperação) para o desafio retornado por /login e gera os tokens.
+// @Description Se o cadastro do MFA tiver sido iniciado em /login/mfa/enroll, o código o confirma e os códigos de recuperação são retornados.
+// @Description Códigos inválidos contam como falhas de login da conta e do IP.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param request body models.MFALoginRequest true "Desafio e código de MFA"
+// @Success 200 {object} models.MFALoginResponse "Tokens gerados"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 401 {string} string "Desafio ou código inválido"
+// @Failure 403 {string} string "Conta inativa"
+// @Failure 429 {string} string "Muitas tentativas de login"
+// @Router /login/mfa [post]
+func LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var request models.MFALoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.MFAToken == "" {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := challengeUser(w, request.MFAToken)
+	if !ok {
+		return
+	}
+	// A conta pode ter sido suspensa depois da primeira etapa do login
+	if !user.Active() {
+		http.Error(w, utils.InactiveAccountMessage(user.Status), http.StatusForbidden)
+		return
+	}
+
+	// O segundo fator está sujeito ao mesmo bloqueio da senha: novos desafios não renovam as tentativas
+	ip := utils.ClientIP(r)
+	wait, err := utils.CheckLoginLock(user.Email, ip)
+	if err != nil {
+		http.Error(w, "Erro ao verificar bloqueio de login", http.StatusInternalServerError)
+		return
+	}
+	if wait > 0 {
+		writeLoginLocked(w, wait)
+		return
+	}
+
+	var response models.MFALoginResponse
+	if user.MFAEnabled {
+		if err := utils.VerifyMFA(*user, request.Code, request.RecoveryCode); err != nil {
+			if errors.Is(err, utils.ErrMFACodeInvalid) {
+				registerMFAFailure(user.Email, ip)
+			}
+			http.Error(w, "Código de MFA inválido", http.StatusUnauthorized)
+			return
+		}
+	} else {
+		// Usuário com role que exige MFA concluindo o cadastro durante o login
+		codes, err := utils.CompleteMFAEnrollment(user, request.Code)
+		if errors.Is(err, utils.ErrMFAEnrollmentNotFound) {
+			http.Error(w, "Cadastro de MFA não iniciado", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, utils.ErrMFACodeInvalid) {
+			registerMFAFailure(user.Email, ip)
+			http.Error(w, "Código de MFA inválido", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao habilitar MFA", http.StatusInternalServerError)
+			return
+		}
+		response.RecoveryCodes = codes
+	}
+
+	if err := utils.ResetLoginFailures(user.Email); err != nil {
+		log.Printf("Erro ao zerar falhas de login: %v", err)
+	}
+
+	// A sessão é aberta na organização escolhida no login
+	organizationID, err := utils.MFAChallengeOrganization(request.MFAToken)
+	if err != nil {
+		http.Error(w, "Desafio de MFA inválido", http.StatusUnauthorized)
+		return
+	}
+	utils.ConsumeMFAChallenge(request.MFAToken)
+
+	info := utils.SessionInfoFromRequest(r)
+	info.OrganizationID = organizationID
+	tokens, err := utils.IssueTokenPair(*user, info)
+	if err != nil {
+		http.Error(w, "Erro ao gerar token", http.StatusInternalServerError)
+		return
+	}
+	response.TokenResponse = *tokens
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// LoginMFAEnroll inicia o cadastro do MFA durante o login
+// @Summary Inicia o cadastro do MFA no login
+// @Description Para usuários cuja role exige MFA e que ainda não o cadastraram. Retorna o segredo e a URI otpauth://;
+// @Description o cadastro é confirmado enviando um código em /login/mfa.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param request body models.MFAEnrollRequest true "Desafio de MFA"
+// @Success 200 {object} models.MFAEnrollmentResponse "Segredo gerado"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 401 {string} string "Desafio inválido"
+// @Failure 409 {string} string "MFA já habilitado"
+// @Router /login/mfa/enroll [post]
+func LoginMFAEnroll(w http.ResponseWriter, r *http.Request) {
+	var request models.MFAEnrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.MFAToken == "" {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := challengeUser(w, request.MFAToken)
+	if !ok {
+		return
+	}
+
+	startEnrollment(w, *user)
+}
+
+// EnrollMFA inicia o cadastro do MFA do usuário autenticado
+// @Summary Inicia o cadastro do MFA
+// @Description Gera um segredo TOTP e a URI otpauth:// para o aplicativo autenticador; o cadastro é confirmado em /me/mfa/confirm
+// @Tags mfa
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} models.MFAEnrollmentResponse "Segredo gerado"
+// @Failure 409 {string} string "MFA já habilitado"
+// @Router /me/mfa/enroll [post]
+func EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	startEnrollment(w, *user)
+}
+
+// ConfirmMFA confirma o cadastro do MFA do usuário autenticado
+// @Summary Confirma o cadastro do MFA
+// @Description Valida o primeiro código gerado pelo aplicativo, habilita o MFA e retorna os códigos de recuperação
+// @Tags mfa
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param request body models.MFACodeRequest true "Código TOTP"
+// @Success 200 {object} models.MFARecoveryCodesResponse "MFA habilitado"
+// @Failure 400 {string} string "Cadastro de MFA não iniciado"
+// @Failure 401 {string} string "Código de MFA inválido"
+// @Router /me/mfa/confirm [post]
+func ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var request models.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if user.MFAEnabled {
+		http.Error(w, "MFA já habilitado", http.StatusConflict)
+		return
+	}
+
+	codes, err := utils.CompleteMFAEnrollment(user, request.Code)
+	if errors.Is(err, utils.ErrMFAEnrollmentNotFound) {
+		http.Error(w, "Cadastro de MFA não iniciado", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, utils.ErrMFACodeInvalid) {
+		http.Error(w, "Código de MFA inválido", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao habilitar MFA", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMFA desabilita o MFA do usuário autenticado
+// @Summary Desabilita o MFA
+// @Description Desabilita o MFA mediante um código TOTP ou de recuperação. Não é permitido quando a role exige MFA.
+// @Tags mfa
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param request body models.MFACodeRequest true "Código TOTP ou de recuperação"
+// @Success 200 {object} map[string]string "MFA desabilitado"
+// @Failure 401 {string} string "Código de MFA inválido"
+// @Failure 403 {string} string "MFA obrigatório para a role"
+// @Router /me/mfa/disable [post]
+func DisableMFA(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var request models.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if user.Role.RequireMFA {
+		http.Error(w, "MFA obrigatório para a sua role", http.StatusForbidden)
+		return
+	}
+	if err := utils.VerifyMFA(*user, request.Code, request.RecoveryCode); err != nil {
+		http.Error(w, "Código de MFA inválido", http.StatusUnauthorized)
+		return
+	}
+
+	if err := utils.DisableMFA(user); err != nil {
+		http.Error(w, "Erro ao desabilitar MFA", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "MFA desabilitado com sucesso"})
+}
+
+// RegenerateRecoveryCodes gera novos códigos de recuperação, invalidando os anteriores
+// @Summary Gera novos códigos de recuperação
+// @Description Invalida os códigos de recuperação anteriores mediante um código TOTP e retorna os novos
+// @Tags mfa
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param request body models.MFACodeRequest true "Código TOTP"
+// @Success 200 {object} models.MFARecoveryCodesResponse "Novos códigos"
+// @Failure 401 {string} string "Código de MFA inválido"
+// @Router /me/mfa/recovery-codes [post]
+func RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var request models.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	// Apenas o código TOTP é aceito: um código de recuperação não pode gerar novos códigos
+	if err := utils.VerifyMFA(*user, request.Code, ""); err != nil {
+		http.Error(w, "Código de MFA inválido", http.StatusUnauthorized)
+		return
+	}
+
+	codes, err := utils.GenerateRecoveryCodes(user.ID)
+	if err != nil {
+		http.Error(w, "Erro ao gerar códigos de recuperação", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// registerMFAFailure contabiliza um código de MFA inválido como uma falha de login da conta e do IP
+func registerMFAFailure(email, ip string) {
+	if err := utils.RegisterLoginFailure(email, ip); err != nil {
+		log.Printf("Erro ao registrar falha de login: %v", err)
+	}
+}
+
+// challengeUser carrega o usuário do desafio de MFA, respondendo com erro se o desafio for inválido
+func challengeUser(w http.ResponseWriter, token string) (*models.User, bool) {
+	userID, err := utils.MFAChallengeUser(token)
+	if err != nil {
+		http.Error(w, "Desafio de MFA inválido ou expirado", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	var user models.User
+	if err := database.DB.Preload("Role").First(&user, userID).Error; err != nil {
+		http.Error(w, "Desafio de MFA inválido ou expirado", http.StatusUnauthorized)
+		return nil, false
+	}
+	return &user, true
+}
+
+// startEnrollment gera o segredo pendente de confirmação para o usuário
+func startEnrollment(w http.ResponseWriter, user models.User) {
+	if user.MFAEnabled {
+		http.Error(w, "MFA já habilitado", http.StatusConflict)
+		return
+	}
+
+	enrollment, err := utils.StartMFAEnrollment(user)
+	if err != nil {
+		http.Error(w, "Erro ao iniciar cadastro de MFA", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollment)
+}
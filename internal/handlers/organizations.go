@@ -0,0 +1,451 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// GetOrganizations lista as organizações
+// @Summary Lista as organizações
+// @Description Retorna todas as organizações no escopo global; em uma organização, apenas ela
+// @Tags organizations
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {array} models.Organization "Organizações"
+// @Failure 500 {string} string "Erro ao buscar organizações"
+// @Router /admin/organizations [get]
+func GetOrganizations(w http.ResponseWriter, r *http.Request) {
+	query := database.DB.Order("id")
+	if organizationID := callerOrg(r); organizationID != 0 {
+		query = query.Where("id = ?", organizationID)
+	}
+
+	var organizations []models.Organization
+	if err := query.Find(&organizations).Error; err != nil {
+		http.Error(w, "Erro ao buscar organizações", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(organizations)
+}
+
+// GetOrganization retorna uma organização pelo ID
+// @Summary Retorna uma organização pelo ID
+// @Tags organizations
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da organização"
+// @Success 200 {object} models.Organization "Organização encontrada"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Organização não encontrada"
+// @Router /admin/organizations/{id} [get]
+func GetOrganization(w http.ResponseWriter, r *http.Request) {
+	organization, ok := findOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(organization)
+}
+
+// CreateOrganization cria uma organização
+// @Summary Cria uma organização
+// @Description Disponível apenas no escopo global
+// @Tags organizations
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param organization body models.OrganizationRequest true "Dados da organização"
+// @Success 201 {object} models.Organization "Organização criada"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 403 {string} string "Organizações só podem ser criadas no escopo global"
+// @Failure 409 {string} string "Já existe uma organização com este nome"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /admin/organizations [post]
+func CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	if callerOrg(r) != 0 {
+		http.Error(w, "Organizações só podem ser criadas no escopo global", http.StatusForbidden)
+		return
+	}
+
+	var request models.OrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	organization := models.Organization{Name: strings.TrimSpace(request.Name)}
+	if organization.Name == "" {
+		errs := validator.Errors{}
+		errs.Add("name", "O nome é obrigatório")
+		writeValidationErrors(w, errs)
+		return
+	}
+	if organizationNameTaken(organization.Name, 0) {
+		http.Error(w, "Já existe uma organização com este nome", http.StatusConflict)
+		return
+	}
+
+	if err := database.DB.Create(&organization).Error; err != nil {
+		http.Error(w, "Erro ao criar organização", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(organization)
+}
+
+// UpdateOrganization renomeia uma organização
+// @Summary Renomeia uma organização
+// @Tags organizations
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID da organização"
+// @Param organization body models.OrganizationRequest true "Dados da organização"
+// @Success 200 {object} models.Organization "Organização atualizada"
+// @Failure 400 {string} string "ID ou dados inválidos"
+// @Failure 404 {string} string "Organização não encontrada"
+// @Failure 409 {string} string "Já existe uma organização com este nome"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /admin/organizations/{id} [put]
+func UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	organization, ok := findOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.OrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		errs := validator.Errors{}
+		errs.Add("name", "O nome é obrigatório")
+		writeValidationErrors(w, errs)
+		return
+	}
+	if organizationNameTaken(name, organization.ID) {
+		http.Error(w, "Já existe uma organização com este nome", http.StatusConflict)
+		return
+	}
+
+	if err := database.DB.Model(organization).Update("name", name).Error; err != nil {
+		http.Error(w, "Erro ao atualizar organização", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(organization)
+}
+
+// DeleteOrganization remove uma organização
+// @Summary Remove uma organização
+// @Description Remove a organização, as suas roles e os seus grupos. Disponível apenas no escopo global e para organizações sem membros.
+// @Tags organizations
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da organização"
+// @Success 200 {object} map[string]string "Organização removida"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 403 {string} string "Organizações só podem ser removidas no escopo global"
+// @Failure 404 {string} string "Organização não encontrada"
+// @Failure 409 {string} string "A organização ainda possui membros"
+// @Router /admin/organizations/{id} [delete]
+func DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	if callerOrg(r) != 0 {
+		http.Error(w, "Organizações só podem ser removidas no escopo global", http.StatusForbidden)
+		return
+	}
+
+	organization, ok := findOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	var members int64
+	database.DB.Model(&models.Membership{}).Where("organization_id = ?", organization.ID).Count(&members)
+	if members > 0 {
+		http.Error(w, "A organização ainda possui membros", http.StatusConflict)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Os grupos da organização são removidos antes das roles que eles recebem
+		groups := tx.Model(&models.Group{}).Unscoped().Select("id").Where("organization_id = ?", organization.ID)
+		if err := tx.Exec("DELETE FROM group_roles WHERE group_id IN (?)", groups).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM group_users WHERE group_id IN (?)", groups).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("organization_id = ?", organization.ID).Delete(&models.Group{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where("organization_id = ?", organization.ID).Delete(&models.RoleGrant{}).Error; err != nil {
+			return err
+		}
+
+		roles := tx.Model(&models.Role{}).Select("id").Where("organization_id = ?", organization.ID)
+		if err := tx.Exec("DELETE FROM role_permissions WHERE role_id IN (?)", roles).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", organization.ID).Delete(&models.Role{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(organization).Error
+	})
+	if err != nil {
+		http.Error(w, "Erro ao remover organização", http.StatusInternalServerError)
+		return
+	}
+	rbac.Invalidate()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Organização removida com sucesso"})
+}
+
+// GetOrganizationMembers lista os membros de uma organização
+// @Summary Lista os membros de uma organização
+// @Description Retorna os membros com a role que cada um tem na organização
+// @Tags organizations
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da organização"
+// @Success 200 {array} models.MembershipResponse "Membros"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Organização não encontrada"
+// @Router /admin/organizations/{id}/members [get]
+func GetOrganizationMembers(w http.ResponseWriter, r *http.Request) {
+	organization, ok := findOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	var memberships []models.Membership
+	err := database.DB.Preload("User").Preload("Role").
+		Where("organization_id = ?", organization.ID).
+		Order("id").Find(&memberships).Error
+	if err != nil {
+		http.Error(w, "Erro ao buscar membros", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewMembershipResponses(memberships))
+}
+
+// SetOrganizationMember adiciona um usuário à organização ou troca a role dele nela
+// @Summary Adiciona um membro ou troca a sua role
+// @Description A role deve ser global ou da própria organização. Em uma organização é possível trocar a role dos
+// @Description membros; a inclusão de novos membros é feita no escopo global. As sessões do usuário são revogadas.
+// @Tags organizations
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID da organização"
+// @Param user_id path int true "ID do usuário"
+// @Param request body models.MembershipRequest true "Role do usuário na organização"
+// @Success 200 {object} models.MembershipResponse "Vínculo atualizado"
+// @Failure 400 {string} string "ID ou dados inválidos"
+// @Failure 404 {string} string "Organização ou usuário não encontrado"
+// @Failure 409 {string} string "Último administrador"
+// @Failure 422 {object} models.ValidationErrorResponse "Role inválida"
+// @Router /admin/organizations/{id}/members/{user_id} [put]
+func SetOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	organization, ok := findOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var request models.MembershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	// Em uma organização só os próprios membros são visíveis
+	var user models.User
+	if err := usersQuery(r).First(&user, userID).Error; err != nil {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+
+	var role models.Role
+	if err := database.DB.First(&role, request.RoleID).Error; err != nil || !utils.RoleUsableIn(role, organization.ID) {
+		errs := validator.Errors{}
+		errs.Add("role_id", "Role não encontrada")
+		writeValidationErrors(w, errs)
+		return
+	}
+	if messages := roleBeyondCaller(r, role.ID); len(messages) > 0 {
+		errs := validator.Errors{}
+		errs.Add("role_id", messages...)
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return setMembership(tx, user.ID, organization.ID, role)
+	})
+	if !writeRoleError(w, err, "Erro ao atualizar membro") {
+		return
+	}
+
+	membership, err := utils.FindMembership(user.ID, organization.ID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar membro", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewMembershipResponse(*membership))
+}
+
+// RemoveOrganizationMember remove um usuário da organização
+// @Summary Remove um membro da organização
+// @Description Remove o vínculo do usuário com a organização e com os grupos dela e revoga as suas sessões. A conta do usuário é mantida.
+// @Tags organizations
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da organização"
+// @Param user_id path int true "ID do usuário"
+// @Success 200 {object} map[string]string "Membro removido"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Organização ou membro não encontrado"
+// @Failure 409 {string} string "Último administrador"
+// @Router /admin/organizations/{id}/members/{user_id} [delete]
+func RemoveOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	organization, ok := findOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	membership, err := utils.FindMembership(uint(userID), organization.ID)
+	if errors.Is(err, utils.ErrNotMember) {
+		http.Error(w, "Membro não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar membro", http.StatusInternalServerError)
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return utils.GuardLastAdmin(tx, organization.ID, func() error {
+			// O usuário também deixa os grupos da organização
+			groups := tx.Model(&models.Group{}).Unscoped().Select("id").Where("organization_id = ?", organization.ID)
+			if err := tx.Exec("DELETE FROM group_users WHERE user_id = ? AND group_id IN (?)", membership.UserID, groups).Error; err != nil {
+				return err
+			}
+			// e perde as concessões temporárias nela
+			err := tx.Model(&models.RoleGrant{}).
+				Where("user_id = ? AND organization_id = ? AND status IN ?", membership.UserID, organization.ID, []string{models.GrantPending, models.GrantApproved}).
+				Update("status", models.GrantRevoked).Error
+			if err != nil {
+				return err
+			}
+			return tx.Delete(membership).Error
+		})
+	})
+	if !writeRoleError(w, err, "Erro ao remover membro") {
+		return
+	}
+	rbac.Invalidate()
+
+	// As sessões abertas na organização deixam de valer
+	if err := utils.RevokeUserSessions(membership.UserID); err != nil {
+		log.Printf("Erro ao revogar sessões do usuário %d após remoção da organização: %v", membership.UserID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Membro removido com sucesso"})
+}
+
+// GetMyOrganizations lista as organizações do usuário autenticado
+// @Summary Lista as minhas organizações
+// @Description Retorna as organizações de que o usuário é membro, com a role dele em cada uma.
+// @Description Para abrir uma sessão em uma delas, informe organization_id no login.
+// @Tags organizations
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {array} models.MembershipResponse "Vínculos do usuário"
+// @Failure 500 {string} string "Erro ao buscar organizações"
+// @Router /me/organizations [get]
+func GetMyOrganizations(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var memberships []models.Membership
+	err := database.DB.Preload("Organization").Preload("Role").
+		Where("user_id = ?", claims.UserID).
+		Order("organization_id").Find(&memberships).Error
+	if err != nil {
+		http.Error(w, "Erro ao buscar organizações", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewMembershipResponses(memberships))
+}
+
+// findOrganization carrega a organização informada na rota. Em uma organização, as demais são tratadas como inexistentes.
+func findOrganization(w http.ResponseWriter, r *http.Request) (*models.Organization, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return nil, false
+	}
+
+	var organization models.Organization
+	if err := database.DB.First(&organization, id).Error; err != nil {
+		http.Error(w, "Organização não encontrada", http.StatusNotFound)
+		return nil, false
+	}
+	if organizationID := callerOrg(r); organizationID != 0 && organizationID != organization.ID {
+		http.Error(w, "Organização não encontrada", http.StatusNotFound)
+		return nil, false
+	}
+	return &organization, true
+}
+
+// organizationNameTaken indica se outra organização já usa o nome informado
+func organizationNameTaken(name string, exceptID uint) bool {
+	var count int64
+	database.DB.Model(&models.Organization{}).Where("name = ? AND id <> ?", name, exceptID).Count(&count)
+	return count > 0
+}
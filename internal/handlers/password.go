@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/mailer"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ForgotPassword envia o link de redefinição de senha
+// @Summary Solicita a redefinição de senha
+// @Description Envia por e-mail um link de redefinição de senha de uso único.
+// @Description A resposta é sempre a mesma, exista ou não uma conta com o e-mail informado.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param request body models.ForgotPasswordRequest true "E-mail da conta"
+// @Success 200 {object} map[string]string "Solicitação recebida"
+// @Failure 400 {string} string "Dados inválidos"
+// @Router /password/forgot [post]
+func ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var request models.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Email == "" {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Where("email = ?", strings.TrimSpace(request.Email)).First(&user).Error; err == nil {
+		mailer.Async(func() {
+			if err := sendPasswordResetEmail(user); err != nil {
+				log.Printf("Erro ao enviar e-mail de redefinição de senha para o usuário %d: %v", user.ID, err)
+			}
+		})
+	}
+
+	// A resposta não revela se o e-mail está cadastrado, nem pelo conteúdo nem pelo tempo: o envio é feito
+	// em segundo plano
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Se o e-mail estiver cadastrado, você receberá as instruções para redefinir a senha",
+	})
+}
+
+// ResetPassword redefine a senha usando o token recebido por e-mail
+// @Summary Redefine a senha
+// @Description Redefine a senha com o token de uso único recebido por e-mail e revoga todas as sessões do usuário
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param request body models.ResetPasswordRequest true "Token e nova senha"
+// @Success 200 {object} map[string]string "Senha redefinida"
+// @Failure 400 {string} string "Dados inválidos ou token inválido"
+// @Failure 422 {object} models.ValidationErrorResponse "Senha fora da política"
+// @Failure 500 {string} string "Erro ao redefinir senha"
+// @Router /password/reset [post]
+func ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var request models.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Token == "" || request.Password == "" {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	// A nova senha é validada antes de consumir o token, para que uma senha fraca não o desperdice
+	userID, err := utils.PasswordResetTokenUser(request.Token)
+	if err != nil {
+		http.Error(w, "Token de redefinição inválido ou expirado", http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		http.Error(w, "Token de redefinição inválido ou expirado", http.StatusBadRequest)
+		return
+	}
+
+	errs := validator.Errors{}
+	errs.Add("password", utils.ValidatePassword(request.Password, user.Email, user.Name)...)
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	if _, err := utils.ConsumePasswordResetToken(request.Token); err != nil {
+		if errors.Is(err, utils.ErrPasswordResetTokenInvalid) {
+			http.Error(w, "Token de redefinição inválido ou expirado", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Erro ao redefinir senha", http.StatusInternalServerError)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Erro ao criptografar senha", http.StatusInternalServerError)
+		return
+	}
+	if err := database.DB.Model(&user).Update("password", string(hashedPassword)).Error; err != nil {
+		http.Error(w, "Erro ao redefinir senha", http.StatusInternalServerError)
+		return
+	}
+
+	// Sessões abertas com a senha antiga deixam de valer
+	if err := utils.RevokeUserSessions(user.ID); err != nil {
+		log.Printf("Erro ao revogar sessões do usuário %d após redefinição de senha: %v", user.ID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Senha redefinida com sucesso"})
+}
+
+// sendPasswordResetEmail gera o token de redefinição e envia o link ao usuário
+func sendPasswordResetEmail(user models.User) error {
+	token, err := utils.CreatePasswordResetToken(user.ID)
+	if err != nil {
+		return err
+	}
+
+	config := settings.LoadSettings()
+	link := fmt.Sprintf("%s/password/reset?token=%s", config.App.URL, url.QueryEscape(token))
+
+	return mailer.Send(mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Redefinição de senha",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"Recebemos uma solicitação para redefinir a senha da sua conta no %s.\n"+
+			"Use o link abaixo em até %s:\n\n%s\n\n"+
+			"Se você não fez esta solicitação, ignore este e-mail.\n",
+			user.Name, config.App.Name, config.Auth.PasswordResetTTL, link),
+	})
+}
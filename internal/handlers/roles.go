@@ -0,0 +1,898 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// GetRoles lista as roles cadastradas
+// @Summary Lista as roles
+// @Description Retorna as roles com as suas permissões e capacidades. Em uma organização, apenas as roles globais e as dela.
+// @Tags roles
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {array} models.Role "Roles cadastradas"
+// @Failure 500 {string} string "Erro ao buscar roles"
+// @Router /admin/roles [get]
+func GetRoles(w http.ResponseWriter, r *http.Request) {
+	var roles []models.Role
+	if err := rolesQuery(r).Preload("Permissions").Order("id").Find(&roles).Error; err != nil {
+		http.Error(w, "Erro ao buscar roles", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(roles)
+}
+
+// GetRole retorna uma role pelo ID
+// @Summary Retorna uma role pelo ID
+// @Description Retorna a role com as suas permissões e capacidades
+// @Tags roles
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da role"
+// @Success 200 {object} models.Role "Role encontrada"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Role não encontrada"
+// @Router /admin/roles/{id} [get]
+func GetRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := findRole(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(role)
+}
+
+// GetRoleCapabilities retorna as capacidades e permissões efetivas de uma role
+// @Summary Capacidades efetivas de uma role
+// @Description Retorna as capacidades e permissões da role somadas às herdadas de toda a hierarquia
+// @Tags roles
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da role"
+// @Success 200 {object} rbac.ResolvedRole "Capacidades efetivas"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Role não encontrada"
+// @Failure 500 {string} string "Erro ao resolver a role"
+// @Router /admin/roles/{id}/capabilities [get]
+func GetRoleCapabilities(w http.ResponseWriter, r *http.Request) {
+	role, ok := findRole(w, r)
+	if !ok {
+		return
+	}
+
+	resolved, err := rbac.Resolve(role.ID)
+	if errors.Is(err, rbac.ErrRoleNotFound) {
+		http.Error(w, "Role não encontrada", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao resolver a role", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resolved)
+}
+
+// CreateRole cria uma role
+// @Summary Cria uma role
+// @Description Cria uma role com nome, capacidades, permissões e, opcionalmente, uma role pai da qual herda capacidades e permissões.
+// @Description Em uma organização, a role pertence a ela; no escopo global, a role é global.
+// @Tags roles
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param role body models.RoleRequest true "Dados da role"
+// @Success 201 {object} models.Role "Role criada"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 409 {string} string "Já existe uma role com este nome"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /admin/roles [post]
+func CreateRole(w http.ResponseWriter, r *http.Request) {
+	var request models.RoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	role := models.Role{
+		Name:         strings.TrimSpace(request.Name),
+		Capabilities: normalizeCapabilities(request.Capabilities),
+	}
+	if organizationID := callerOrg(r); organizationID != 0 {
+		role.OrganizationID = &organizationID
+	}
+	if request.RequireMFA != nil {
+		role.RequireMFA = *request.RequireMFA
+	}
+
+	errs := validator.Errors{}
+	if role.Name == "" {
+		errs.Add("name", "O nome é obrigatório")
+	}
+	errs.Add("capabilities", validateCapabilities(role.Capabilities)...)
+	errs.Add("capabilities", capabilitiesBeyondCaller(r, role.Capabilities)...)
+	permissions, messages := loadPermissions(request.PermissionIDs)
+	errs.Add("permission_ids", messages...)
+	if request.ParentID != nil && *request.ParentID != 0 {
+		if messages := validateParent(role, *request.ParentID); len(messages) > 0 {
+			errs.Add("parent_id", messages...)
+		} else {
+			errs.Add("parent_id", roleBeyondCaller(r, *request.ParentID)...)
+		}
+		role.ParentID = request.ParentID
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+	role.Permissions = permissions
+
+	if roleNameTaken(role, role.Name) {
+		http.Error(w, "Já existe uma role com este nome", http.StatusConflict)
+		return
+	}
+
+	if err := database.DB.Create(&role).Error; err != nil {
+		http.Error(w, "Erro ao criar role", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(role)
+}
+
+// UpdateRole atualiza uma role
+// @Summary Atualiza uma role
+// @Description Atualiza apenas os campos informados (parent_id 0 remove a role pai).
+// @Description Não é permitido formar ciclos na hierarquia nem retirar o acesso total do último administrador.
+// @Tags roles
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID da role"
+// @Param role body models.RoleRequest true "Campos a atualizar"
+// @Success 200 {object} models.Role "Role atualizada"
+// @Failure 400 {string} string "ID ou dados inválidos"
+// @Failure 404 {string} string "Role não encontrada"
+// @Failure 409 {string} string "Nome em uso ou último administrador"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /admin/roles/{id} [put]
+func UpdateRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := findOwnedRole(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.RoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	errs := validator.Errors{}
+	name := strings.TrimSpace(request.Name)
+	if name != "" {
+		role.Name = name
+	}
+	var capabilities []string
+	if request.Capabilities != nil {
+		capabilities = normalizeCapabilities(request.Capabilities)
+		errs.Add("capabilities", validateCapabilities(capabilities)...)
+		errs.Add("capabilities", capabilitiesBeyondCaller(r, capabilities)...)
+	}
+	var permissions []models.Permission
+	if request.PermissionIDs != nil {
+		var messages []string
+		permissions, messages = loadPermissions(request.PermissionIDs)
+		errs.Add("permission_ids", messages...)
+	}
+	if request.ParentID != nil && *request.ParentID != 0 {
+		if messages := validateParent(*role, *request.ParentID); len(messages) > 0 {
+			errs.Add("parent_id", messages...)
+		} else {
+			errs.Add("parent_id", roleBeyondCaller(r, *request.ParentID)...)
+		}
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	if name != "" && roleNameTaken(*role, name) {
+		http.Error(w, "Já existe uma role com este nome", http.StatusConflict)
+		return
+	}
+	if request.RequireMFA != nil {
+		role.RequireMFA = *request.RequireMFA
+	}
+
+	if request.ParentID != nil {
+		role.ParentID = request.ParentID
+		if *request.ParentID == 0 {
+			role.ParentID = nil
+		}
+	}
+
+	// Capacidades e role pai mudam o acesso herdado; a alteração não pode deixar o sistema sem administradores
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return utils.GuardLastAdmin(tx, roleOrg(*role), func() error {
+			if role.ParentID != nil {
+				if err := rbac.ValidateParent(tx, role.ID, *role.ParentID); err != nil {
+					return err
+				}
+			}
+			if request.Capabilities != nil {
+				role.Capabilities = capabilities
+			}
+			if err := tx.Model(role).Select("Name", "RequireMFA", "Capabilities", "ParentID").Updates(role).Error; err != nil {
+				return err
+			}
+			if request.PermissionIDs != nil {
+				return tx.Model(role).Association("Permissions").Replace(permissions)
+			}
+			return nil
+		})
+	})
+	if !writeRoleError(w, err, "Erro ao atualizar role") {
+		return
+	}
+	rbac.Invalidate()
+
+	database.DB.Preload("Permissions").First(role, role.ID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(role)
+}
+
+// DeleteRole remove uma role
+// @Summary Remove uma role
+// @Description Remove a role se nenhum usuário a possuir e nenhuma role herdar dela
+// @Tags roles
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da role"
+// @Success 200 {object} map[string]string "Role removida"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Role não encontrada"
+// @Failure 409 {string} string "A role ainda possui usuários, grupos, concessões ou roles filhas"
+// @Router /admin/roles/{id} [delete]
+func DeleteRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := findOwnedRole(w, r)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var users int64
+		if err := tx.Model(&models.User{}).Where("role_id = ?", role.ID).Count(&users).Error; err != nil {
+			return err
+		}
+		var members int64
+		if err := tx.Model(&models.Membership{}).Where("role_id = ?", role.ID).Count(&members).Error; err != nil {
+			return err
+		}
+		var groups int64
+		if err := tx.Table("group_roles").Where("role_id = ?", role.ID).Count(&groups).Error; err != nil {
+			return err
+		}
+		var grants int64
+		err := tx.Model(&models.RoleGrant{}).
+			Where("role_id = ? AND status IN ? AND expires_at > ?", role.ID, []string{models.GrantPending, models.GrantApproved}, time.Now()).
+			Count(&grants).Error
+		if err != nil {
+			return err
+		}
+		if users > 0 || members > 0 || groups > 0 || grants > 0 {
+			return utils.ErrRoleInUse
+		}
+
+		var children int64
+		if err := tx.Model(&models.Role{}).Where("parent_id = ?", role.ID).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return utils.ErrRoleHasChildren
+		}
+
+		if err := tx.Model(role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(role).Error
+	})
+	if !writeRoleError(w, err, "Erro ao remover role") {
+		return
+	}
+	rbac.Invalidate()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Role removida com sucesso"})
+}
+
+// UpdateRoleCapabilities substitui as capacidades de uma role
+// @Summary Substitui as capacidades de uma role
+// @Description Substitui a lista de capacidades próprias da role. Não é permitido retirar o acesso total do último administrador.
+// @Tags roles
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID da role"
+// @Param request body models.RoleCapabilitiesRequest true "Capacidades"
+// @Success 200 {object} models.Role "Role atualizada"
+// @Failure 400 {string} string "ID ou dados inválidos"
+// @Failure 404 {string} string "Role não encontrada"
+// @Failure 409 {string} string "Último administrador"
+// @Failure 422 {object} models.ValidationErrorResponse "Capacidades inválidas"
+// @Router /admin/roles/{id}/capabilities [put]
+func UpdateRoleCapabilities(w http.ResponseWriter, r *http.Request) {
+	role, ok := findOwnedRole(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.RoleCapabilitiesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	capabilities := normalizeCapabilities(request.Capabilities)
+	errs := validator.Errors{}
+	errs.Add("capabilities", validateCapabilities(capabilities)...)
+	errs.Add("capabilities", capabilitiesBeyondCaller(r, capabilities)...)
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return setRoleCapabilities(tx, role, capabilities)
+	})
+	if !writeRoleError(w, err, "Erro ao atualizar capacidades") {
+		return
+	}
+	rbac.Invalidate()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(role)
+}
+
+// UpdateRolePermissions substitui as permissões de uma role
+// @Summary Substitui as permissões de uma role
+// @Description Substitui as permissões associadas à role
+// @Tags roles
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID da role"
+// @Param request body models.RolePermissionsRequest true "IDs das permissões"
+// @Success 200 {object} models.Role "Role atualizada"
+// @Failure 400 {string} string "ID ou dados inválidos"
+// @Failure 404 {string} string "Role não encontrada"
+// @Failure 422 {object} models.ValidationErrorResponse "Permissões inválidas"
+// @Router /admin/roles/{id}/permissions [put]
+func UpdateRolePermissions(w http.ResponseWriter, r *http.Request) {
+	role, ok := findOwnedRole(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.RolePermissionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	permissions, messages := loadPermissions(request.PermissionIDs)
+	if len(messages) > 0 {
+		errs := validator.Errors{}
+		errs.Add("permission_ids", messages...)
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	if err := database.DB.Model(role).Association("Permissions").Replace(permissions); err != nil {
+		http.Error(w, "Erro ao atualizar permissões", http.StatusInternalServerError)
+		return
+	}
+	rbac.Invalidate()
+
+	// A resposta traz as permissões como ficaram gravadas
+	if err := database.DB.Preload("Permissions").First(role, role.ID).Error; err != nil {
+		http.Error(w, "Erro ao atualizar permissões", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(role)
+}
+
+// GetRoleCacheStats retorna os contadores do cache de roles resolvidas
+// @Summary Estatísticas do cache de roles
+// @Description Retorna acertos, faltas, descartes e invalidações do cache de roles desta instância
+// @Tags roles
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} rbac.CacheStats "Contadores do cache"
+// @Router /admin/roles/cache [get]
+func GetRoleCacheStats(w http.ResponseWriter, r *http.Request) {
+	stats := rbac.CacheStats{}
+	if rbac.DefaultCache != nil {
+		stats = rbac.DefaultCache.Stats()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetPermissions lista as permissões cadastradas
+// @Summary Lista as permissões
+// @Description Retorna todas as permissões cadastradas
+// @Tags roles
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {array} models.Permission "Permissões cadastradas"
+// @Failure 500 {string} string "Erro ao buscar permissões"
+// @Router /admin/permissions [get]
+func GetPermissions(w http.ResponseWriter, r *http.Request) {
+	var permissions []models.Permission
+	if err := database.DB.Order("id").Find(&permissions).Error; err != nil {
+		http.Error(w, "Erro ao buscar permissões", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(permissions)
+}
+
+// CreatePermission cria uma permissão
+// @Summary Cria uma permissão
+// @Description Cria uma permissão que pode ser associada às roles
+// @Tags roles
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param permission body models.PermissionRequest true "Nome da permissão"
+// @Success 201 {object} models.Permission "Permissão criada"
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 403 {string} string "Permissões só podem ser criadas no escopo global"
+// @Failure 409 {string} string "Já existe uma permissão com este nome"
+// @Failure 422 {object} models.ValidationErrorResponse "Campos inválidos"
+// @Router /admin/permissions [post]
+func CreatePermission(w http.ResponseWriter, r *http.Request) {
+	// As permissões são compartilhadas por todas as organizações
+	if callerOrg(r) != 0 {
+		http.Error(w, "Permissões só podem ser criadas no escopo global", http.StatusForbidden)
+		return
+	}
+
+	var request models.PermissionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	permission := models.Permission{Name: strings.TrimSpace(request.Name)}
+	if permission.Name == "" {
+		errs := validator.Errors{}
+		errs.Add("name", "O nome é obrigatório")
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	var existing int64
+	database.DB.Model(&models.Permission{}).Where("name = ?", permission.Name).Count(&existing)
+	if existing > 0 {
+		http.Error(w, "Já existe uma permissão com este nome", http.StatusConflict)
+		return
+	}
+
+	if err := database.DB.Create(&permission).Error; err != nil {
+		http.Error(w, "Erro ao criar permissão", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(permission)
+}
+
+// DeletePermission remove uma permissão
+// @Summary Remove uma permissão
+// @Description Remove a permissão e a desassocia de todas as roles
+// @Tags roles
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID da permissão"
+// @Success 200 {object} map[string]string "Permissão removida"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 403 {string} string "Permissões só podem ser removidas no escopo global"
+// @Failure 404 {string} string "Permissão não encontrada"
+// @Router /admin/permissions/{id} [delete]
+func DeletePermission(w http.ResponseWriter, r *http.Request) {
+	if callerOrg(r) != 0 {
+		http.Error(w, "Permissões só podem ser removidas no escopo global", http.StatusForbidden)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var permission models.Permission
+	if err := database.DB.First(&permission, id).Error; err != nil {
+		http.Error(w, "Permissão não encontrada", http.StatusNotFound)
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM role_permissions WHERE permission_id = ?", permission.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&permission).Error
+	})
+	if err != nil {
+		http.Error(w, "Erro ao remover permissão", http.StatusInternalServerError)
+		return
+	}
+	rbac.Invalidate()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Permissão removida com sucesso"})
+}
+
+// AssignUserRole atribui uma role a um usuário
+// @Summary Atribui uma role a um usuário
+// @Description Troca a role do usuário e revoga as suas sessões, para que os novos tokens reflitam a role.
+// @Description Em uma organização, troca a role do usuário nela. Não é permitido retirar a role administrativa do último administrador.
+// @Tags roles
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID do usuário"
+// @Param request body models.AssignRoleRequest true "ID da role"
+// @Success 200 {object} models.AdminUserResponse "Usuário atualizado"
+// @Failure 400 {string} string "ID ou dados inválidos"
+// @Failure 404 {string} string "Usuário não encontrado"
+// @Failure 409 {string} string "Último administrador"
+// @Failure 422 {object} models.ValidationErrorResponse "Role inválida"
+// @Router /admin/users/{id}/role [put]
+func AssignUserRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var request models.AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := usersQuery(r).Preload("Role").First(&user, id).Error; err != nil {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+
+	role, messages := loadAssignableRole(r, request.RoleID)
+	if len(messages) > 0 {
+		errs := validator.Errors{}
+		errs.Add("role_id", messages...)
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return assignRole(tx, &user, *role, callerOrg(r))
+	})
+	if !writeRoleError(w, err, "Erro ao atribuir role") {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewAdminUserResponse(user))
+}
+
+// assignRole troca a role do usuário (ou, em uma organização, a role dele nela), impedindo que o
+// último administrador perca o acesso total
+func assignRole(tx *gorm.DB, user *models.User, role models.Role, organizationID uint) error {
+	if organizationID != 0 {
+		return setMembership(tx, user.ID, organizationID, role)
+	}
+	if user.RoleID == role.ID {
+		return nil
+	}
+
+	err := utils.GuardLastAdmin(tx, 0, func() error {
+		return tx.Model(user).Update("role_id", role.ID).Error
+	})
+	if err != nil {
+		return err
+	}
+	user.RoleID = role.ID
+	user.Role = role
+
+	// Os tokens emitidos carregam a role antiga
+	if err := utils.RevokeUserSessions(user.ID); err != nil {
+		log.Printf("Erro ao revogar sessões do usuário %d após troca de role: %v", user.ID, err)
+	}
+	return nil
+}
+
+// setMembership adiciona o usuário à organização ou troca a role dele nela, impedindo que a organização
+// fique sem administradores
+func setMembership(tx *gorm.DB, userID, organizationID uint, role models.Role) error {
+	var membership models.Membership
+	err := tx.Where("user_id = ? AND organization_id = ?", userID, organizationID).First(&membership).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if membership.ID != 0 && membership.RoleID == role.ID {
+		return nil
+	}
+
+	err = utils.GuardLastAdmin(tx, organizationID, func() error {
+		if membership.ID == 0 {
+			membership = models.Membership{UserID: userID, OrganizationID: organizationID, RoleID: role.ID}
+			return tx.Create(&membership).Error
+		}
+		return tx.Model(&membership).Update("role_id", role.ID).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// Os tokens emitidos na organização carregam a role antiga
+	if err := utils.RevokeUserSessions(userID); err != nil {
+		log.Printf("Erro ao revogar sessões do usuário %d após troca de role: %v", userID, err)
+	}
+	return nil
+}
+
+// loadAssignableRole carrega a role a ser atribuída no escopo de quem chama, retornando as mensagens de erro
+func loadAssignableRole(r *http.Request, roleID uint) (*models.Role, []string) {
+	role, messages := loadRequestableRole(r, roleID)
+	if len(messages) > 0 {
+		return nil, messages
+	}
+	if messages := roleBeyondCaller(r, role.ID); len(messages) > 0 {
+		return nil, messages
+	}
+	return role, nil
+}
+
+// loadRequestableRole é como loadAssignableRole, mas sem exigir que quem chama possua as capacidades da role:
+// uma concessão solicitada só vale depois de aprovada por quem as possui (ver ApproveGrant)
+func loadRequestableRole(r *http.Request, roleID uint) (*models.Role, []string) {
+	var role models.Role
+	if err := rolesQuery(r).First(&role, roleID).Error; err != nil {
+		return nil, []string{"Role não encontrada"}
+	}
+	if !utils.RoleUsableIn(role, callerOrg(r)) {
+		return nil, []string{"Roles de uma organização só podem ser atribuídas nela"}
+	}
+	return &role, nil
+}
+
+// capabilitiesBeyondCaller retorna as mensagens de erro para as capacidades que quem chama não possui.
+// Em uma organização um administrador não concede mais do que tem; no escopo global não há restrição.
+func capabilitiesBeyondCaller(r *http.Request, capabilities []string) []string {
+	if callerOrg(r) == 0 {
+		return nil
+	}
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		return []string{"Erro ao obter informações do usuário"}
+	}
+	caller, err := utils.ResolveClaims(claims)
+	if err != nil {
+		return []string{"Erro ao verificar as suas capacidades"}
+	}
+
+	var messages []string
+	for _, capability := range capabilities {
+		if !rbac.Covers(caller.Capabilities, capability) {
+			messages = append(messages, "Capacidade que você não possui: "+capability)
+		}
+	}
+	return messages
+}
+
+// roleBeyondCaller é como capabilitiesBeyondCaller, mas para as capacidades efetivas (com a herança) da role
+func roleBeyondCaller(r *http.Request, roleID uint) []string {
+	if callerOrg(r) == 0 {
+		return nil
+	}
+	role, err := rbac.Resolve(roleID)
+	if err != nil {
+		return []string{"Erro ao verificar as capacidades da role"}
+	}
+	if len(capabilitiesBeyondCaller(r, role.Capabilities)) > 0 {
+		return []string{"A role " + role.Name + " concede capacidades que você não possui"}
+	}
+	return nil
+}
+
+// setRoleCapabilities grava as capacidades da role, impedindo que o último administrador perca o acesso total
+func setRoleCapabilities(tx *gorm.DB, role *models.Role, capabilities []string) error {
+	return utils.GuardLastAdmin(tx, roleOrg(*role), func() error {
+		role.Capabilities = capabilities
+		return tx.Model(role).Update("capabilities", capabilities).Error
+	})
+}
+
+// findRole carrega a role informada na rota, respondendo com erro se ela não existir
+func findRole(w http.ResponseWriter, r *http.Request) (*models.Role, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return nil, false
+	}
+
+	var role models.Role
+	if err := rolesQuery(r).Preload("Permissions").First(&role, id).Error; err != nil {
+		http.Error(w, "Role não encontrada", http.StatusNotFound)
+		return nil, false
+	}
+	return &role, true
+}
+
+// findOwnedRole é como findRole, mas exige que quem chama possa alterar a role:
+// em uma organização, as roles globais são apenas para leitura
+func findOwnedRole(w http.ResponseWriter, r *http.Request) (*models.Role, bool) {
+	role, ok := findRole(w, r)
+	if !ok {
+		return nil, false
+	}
+	if organizationID := callerOrg(r); organizationID != 0 && roleOrg(*role) != organizationID {
+		http.Error(w, "Roles globais só podem ser alteradas no escopo global", http.StatusForbidden)
+		return nil, false
+	}
+	return role, true
+}
+
+// roleOrg retorna a organização dona da role; 0 para roles globais
+func roleOrg(role models.Role) uint {
+	if role.OrganizationID == nil {
+		return 0
+	}
+	return *role.OrganizationID
+}
+
+// writeRoleError traduz os erros das operações com roles; retorna true se não houve erro
+func writeRoleError(w http.ResponseWriter, err error, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, utils.ErrRoleInUse):
+		http.Error(w, "A role ainda possui usuários ou grupos", http.StatusConflict)
+	case errors.Is(err, utils.ErrRoleHasChildren):
+		http.Error(w, "Outras roles herdam desta role", http.StatusConflict)
+	case errors.Is(err, rbac.ErrRoleCycle):
+		http.Error(w, "A hierarquia de roles formaria um ciclo", http.StatusConflict)
+	case errors.Is(err, utils.ErrLastAdmin):
+		http.Error(w, "Operação não permitida: o sistema ficaria sem administradores", http.StatusConflict)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+	return false
+}
+
+// validateParent retorna as mensagens de erro para a role pai informada. Roles globais só herdam de roles
+// globais; roles de uma organização herdam de roles globais ou da mesma organização.
+func validateParent(role models.Role, parentID uint) []string {
+	var parent models.Role
+	if err := database.DB.First(&parent, parentID).Error; err != nil || !utils.RoleUsableIn(parent, roleOrg(role)) {
+		return []string{"Role pai não encontrada"}
+	}
+
+	err := rbac.ValidateParent(database.DB, role.ID, parentID)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, rbac.ErrRoleNotFound):
+		return []string{"Role pai não encontrada"}
+	case errors.Is(err, rbac.ErrRoleCycle):
+		return []string{"A hierarquia de roles formaria um ciclo"}
+	default:
+		return []string{"Erro ao verificar a role pai"}
+	}
+}
+
+// roleNameTaken indica se outra role visível no escopo da role já usa o nome informado.
+// Uma role global não pode repetir o nome de nenhuma role; a de uma organização, o de uma role global
+// ou da mesma organização. Assim uma organização não cria uma role com o nome de uma role global.
+func roleNameTaken(role models.Role, name string) bool {
+	query := database.DB.Model(&models.Role{}).Where("name = ? AND id <> ?", name, role.ID)
+	if organizationID := roleOrg(role); organizationID != 0 {
+		query = query.Scopes(utils.TenantRoles(organizationID))
+	}
+
+	var count int64
+	query.Count(&count)
+	return count > 0
+}
+
+// loadPermissions carrega as permissões pelos IDs, retornando mensagens para os IDs inexistentes
+func loadPermissions(ids []uint) ([]models.Permission, []string) {
+	if len(ids) == 0 {
+		return []models.Permission{}, nil
+	}
+
+	var permissions []models.Permission
+	if err := database.DB.Where("id IN ?", ids).Find(&permissions).Error; err != nil {
+		return nil, []string{"Erro ao buscar permissões"}
+	}
+
+	found := make(map[uint]bool, len(permissions))
+	for _, permission := range permissions {
+		found[permission.ID] = true
+	}
+	var messages []string
+	for _, id := range ids {
+		if !found[id] {
+			messages = append(messages, "Permissão não encontrada: "+strconv.FormatUint(uint64(id), 10))
+		}
+	}
+	return permissions, messages
+}
+
+// normalizeCapabilities remove espaços, entradas vazias e duplicadas
+func normalizeCapabilities(capabilities []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool, len(capabilities))
+	for _, capability := range capabilities {
+		capability = strings.TrimSpace(capability)
+		if capability == "" || seen[capability] {
+			continue
+		}
+		seen[capability] = true
+		normalized = append(normalized, capability)
+	}
+	return normalized
+}
+
+// validateCapabilities verifica o formato das capacidades: "*" ou "verbo:recurso[:escopo]",
+// com "*" em qualquer segmento e "!" no início para negar
+func validateCapabilities(capabilities []string) []string {
+	var messages []string
+	for _, capability := range capabilities {
+		pattern := strings.TrimPrefix(capability, "!")
+		if pattern == rbac.CapabilityAll {
+			continue
+		}
+
+		segments := strings.Split(pattern, ":")
+		valid := len(segments) >= 2 && !strings.ContainsAny(pattern, " \t!")
+		for _, segment := range segments {
+			if segment == "" {
+				valid = false
+			}
+		}
+		if !valid {
+			messages = append(messages, "Capacidade inválida: "+capability)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/utils"
+)
+
+// GetMySessions lista as sessões ativas do usuário autenticado
+// @Summary Lista as minhas sessões
+// @Description Retorna as sessões ativas do usuário autenticado, com IP, User-Agent e datas de criação e último uso
+// @Tags sessions
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {array} models.Session "Sessões ativas"
+// @Failure 500 {string} string "Erro ao buscar sessões"
+// @Router /me/sessions [get]
+func GetMySessions(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	sessions, err := utils.ListUserSessions(claims.UserID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar sessões", http.StatusInternalServerError)
+		return
+	}
+
+	// Marca a sessão usada na requisição atual
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == claims.SessionID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+// DeleteMySession revoga uma sessão do usuário autenticado
+// @Summary Revoga uma das minhas sessões
+// @Description Revoga apenas a sessão informada; as demais sessões do usuário continuam válidas
+// @Tags sessions
+// @Security BearerAuth
+// @Produce  json
+// @Param id path string true "ID da sessão"
+// @Success 200 {object} map[string]string "Sessão revogada"
+// @Failure 404 {string} string "Sessão não encontrada"
+// @Failure 500 {string} string "Erro ao revogar sessão"
+// @Router /me/sessions/{id} [delete]
+func DeleteMySession(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	revokeSession(w, claims.UserID, 0, mux.Vars(r)["id"])
+}
+
+// GetUserSessions lista as sessões ativas de um usuário
+// @Summary Lista as sessões de um usuário
+// @Description Retorna as sessões ativas do usuário informado. Em uma organização, apenas as sessões abertas nela.
+// @Tags sessions
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do usuário"
+// @Success 200 {array} models.Session "Sessões ativas"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Usuário não encontrado"
+// @Failure 500 {string} string "Erro ao buscar sessões"
+// @Router /admin/users/{id}/sessions [get]
+func GetUserSessions(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var user models.User
+	if err := usersQuery(r).First(&user, id).Error; err != nil {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+
+	sessions, err := utils.ListUserSessions(user.ID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar sessões", http.StatusInternalServerError)
+		return
+	}
+
+	// As sessões abertas em outras organizações (ou no escopo global) não pertencem à organização de quem chama
+	organizationID := callerOrg(r)
+	visible := make([]models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if sessionVisible(session, organizationID) {
+			visible = append(visible, session)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(visible)
+}
+
+// DeleteUserSession revoga uma sessão de um usuário
+// @Summary Revoga uma sessão de um usuário
+// @Description Revoga apenas a sessão informada do usuário. Em uma organização, apenas as sessões abertas nela.
+// @Tags sessions
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "ID do usuário"
+// @Param sid path string true "ID da sessão"
+// @Success 200 {object} map[string]string "Sessão revogada"
+// @Failure 400 {string} string "ID inválido"
+// @Failure 404 {string} string "Sessão não encontrada"
+// @Failure 500 {string} string "Erro ao revogar sessão"
+// @Router /admin/users/{id}/sessions/{sid} [delete]
+func DeleteUserSession(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	// Em uma organização, apenas as sessões dos seus membros
+	var user models.User
+	if err := usersQuery(r).First(&user, id).Error; err != nil {
+		http.Error(w, "Sessão não encontrada", http.StatusNotFound)
+		return
+	}
+
+	revokeSession(w, user.ID, callerOrg(r), params["sid"])
+}
+
+// revokeSession revoga a sessão se ela pertencer ao usuário informado e for visível na organização
+// (0 não restringe)
+func revokeSession(w http.ResponseWriter, userID, organizationID uint, sessionID string) {
+	session, err := utils.GetSession(sessionID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar sessão", http.StatusInternalServerError)
+		return
+	}
+	// Sessões de outros usuários são tratadas como inexistentes
+	if session == nil || session.UserID != userID || !sessionVisible(*session, organizationID) {
+		http.Error(w, "Sessão não encontrada", http.StatusNotFound)
+		return
+	}
+
+	if err := utils.RevokeSession(session.ID); err != nil {
+		http.Error(w, "Erro ao revogar sessão", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Sessão revogada com sucesso"})
+}
+
+// sessionVisible indica se a sessão é visível na organização: no escopo global (0) todas são
+func sessionVisible(session models.Session, organizationID uint) bool {
+	return organizationID == 0 || session.OrganizationID == organizationID
+}
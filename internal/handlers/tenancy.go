@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"gorm.io/gorm"
+)
+
+// callerOrg retorna a organização da sessão do usuário autenticado; 0 no escopo global ou sem autenticação
+func callerOrg(r *http.Request) uint {
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		return 0
+	}
+	return claims.OrgID
+}
+
+// usersQuery inicia uma consulta de usuários restrita à organização de quem chama
+func usersQuery(r *http.Request) *gorm.DB {
+	return database.DB.Scopes(utils.TenantUsers(callerOrg(r)))
+}
+
+// rolesQuery inicia uma consulta de roles restrita às roles globais e às da organização de quem chama
+func rolesQuery(r *http.Request) *gorm.DB {
+	return database.DB.Scopes(utils.TenantRoles(callerOrg(r)))
+}
+
+// accountOutsideCaller indica se, em uma organização, a conta do usuário também é usada fora dela: ele é membro
+// de outras organizações ou administra o escopo global. Alterações que valem para a conta inteira (e-mail, senha,
+// situação, exclusão) ficam então com o escopo global. No escopo global retorna sempre false.
+func accountOutsideCaller(r *http.Request, user models.User) (bool, error) {
+	organizationID := callerOrg(r)
+	if organizationID == 0 {
+		return false, nil
+	}
+
+	var others int64
+	if err := database.DB.Model(&models.Membership{}).Where("user_id = ? AND organization_id <> ?", user.ID, organizationID).Count(&others).Error; err != nil {
+		return false, err
+	}
+	if others > 0 {
+		return true, nil
+	}
+
+	global, err := rbac.ResolveUser(user.ID, 0, user.RoleID)
+	if err != nil {
+		return false, err
+	}
+	return global.IsAdmin(), nil
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// maxPageLimit é o maior número de itens por página aceito nas listagens
+const maxPageLimit = 100
+
+// userSortColumns são os campos pelos quais GET /users pode ser ordenado
+var userSortColumns = map[string]string{
+	"id":          "users.id",
+	"name":        "users.name",
+	"email":       "users.email",
+	"created_at":  "users.created_at",
+	"updated_at":  "users.updated_at",
+	"verified_at": "users.verified_at",
+}
+
+// userNullableSorts são os campos de ordenação que admitem nulos e, por isso, não servem de chave para cursores
+var userNullableSorts = map[string]bool{"verified_at": true}
+
+// userListing é a consulta de GET /users já interpretada
+type userListing struct {
+	filters    func(*gorm.DB) *gorm.DB
+	sort       []utils.SortField // Inclui o desempate por id
+	cursorMode bool
+	cursor     *utils.Cursor
+	position   []interface{} // Valores do cursor, convertidos para os tipos das colunas
+}
+
+// parseUserListing interpreta a busca, os filtros, a ordenação e o cursor de GET /users.
+// Os erros são registrados por parâmetro.
+func parseUserListing(r *http.Request) (userListing, validator.Errors) {
+	query := r.URL.Query()
+	errs := validator.Errors{}
+	var conditions []func(*gorm.DB) *gorm.DB
+
+	if term := strings.TrimSpace(query.Get("q")); term != "" {
+		pattern := utils.LikePattern(term)
+		conditions = append(conditions, func(db *gorm.DB) *gorm.DB {
+			return db.Where("(users.name ILIKE ? OR users.email ILIKE ?)", pattern, pattern)
+		})
+	}
+
+	if value := query.Get("role_id"); value != "" {
+		roleID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || roleID == 0 {
+			errs.Add("role_id", "ID de role inválido")
+		} else if organizationID := callerOrg(r); organizationID != 0 {
+			// Na organização, vale a role do vínculo, não a role global do usuário
+			conditions = append(conditions, func(db *gorm.DB) *gorm.DB {
+				return db.Where("users.id IN (SELECT user_id FROM memberships WHERE organization_id = ? AND role_id = ?)", organizationID, roleID)
+			})
+		} else {
+			conditions = append(conditions, func(db *gorm.DB) *gorm.DB {
+				return db.Where("users.role_id = ?", roleID)
+			})
+		}
+	}
+
+	switch status := query.Get("status"); status {
+	case "":
+	case models.UserActive, models.UserPending, models.UserSuspended, models.UserBanned:
+		conditions = append(conditions, func(db *gorm.DB) *gorm.DB {
+			return db.Where("users.status = ?", status)
+		})
+	default:
+		errs.Add("status", "Situação inválida: use active, pending, suspended ou banned")
+	}
+
+	switch verified := query.Get("verified"); verified {
+	case "":
+	case "true":
+		conditions = append(conditions, func(db *gorm.DB) *gorm.DB {
+			return db.Where("users.verified_at IS NOT NULL")
+		})
+	case "false":
+		conditions = append(conditions, func(db *gorm.DB) *gorm.DB {
+			return db.Where("users.verified_at IS NULL")
+		})
+	default:
+		errs.Add("verified", "Use true ou false")
+	}
+
+	if value := query.Get("created_from"); value != "" {
+		from, err := utils.ParseDateBound(value, false)
+		if err != nil {
+			errs.Add("created_from", err.Error())
+		} else {
+			conditions = append(conditions, func(db *gorm.DB) *gorm.DB {
+				return db.Where("users.created_at >= ?", from)
+			})
+		}
+	}
+	if value := query.Get("created_to"); value != "" {
+		to, err := utils.ParseDateBound(value, true)
+		if err != nil {
+			errs.Add("created_to", err.Error())
+		} else {
+			conditions = append(conditions, func(db *gorm.DB) *gorm.DB {
+				return db.Where("users.created_at < ?", to)
+			})
+		}
+	}
+
+	listing := userListing{
+		filters: func(db *gorm.DB) *gorm.DB {
+			return db.Scopes(conditions...)
+		},
+	}
+
+	fields, err := utils.ParseSort(query.Get("sort"), userSortColumns)
+	if err != nil {
+		errs.Add("sort", err.Error())
+	}
+	listing.sort = utils.WithTiebreaker(fields, "id", "users.id")
+
+	switch mode := query.Get("pagination"); mode {
+	case "", "page":
+	case "cursor":
+		listing.cursorMode = true
+	default:
+		errs.Add("pagination", "Modo de paginação inválido: use page ou cursor")
+	}
+
+	raw := query.Get("cursor")
+	if raw == "" && !listing.cursorMode {
+		return listing, errs
+	}
+	listing.cursorMode = true
+	for _, field := range fields {
+		if userNullableSorts[field.Name] {
+			errs.Add("sort", "A paginação por cursor não aceita ordenação por "+field.Name)
+		}
+	}
+	if raw == "" || !errs.Empty() {
+		return listing, errs
+	}
+
+	cursor, err := utils.DecodeCursor(raw)
+	if err == nil && cursor.Sort != utils.SortString(listing.sort) {
+		err = utils.ErrInvalidCursor
+	}
+	if err == nil {
+		listing.position, err = parseUserCursorValues(listing.sort, cursor.Values)
+	}
+	if err != nil {
+		errs.Add("cursor", "Cursor inválido ou gerado com outra ordenação")
+		return listing, errs
+	}
+	listing.cursor = &cursor
+	return listing, errs
+}
+
+// listUsersByCursor busca uma página a partir do cursor (ou a primeira, sem cursor), sem OFFSET: a posição
+// é uma condição sobre as colunas de ordenação. Retorna os cursores da página seguinte e da anterior, vazios
+// quando não há mais registros naquela direção.
+func listUsersByCursor(r *http.Request, listing userListing, limit int) ([]models.User, string, string, error) {
+	backward := listing.cursor != nil && listing.cursor.Backward
+	order := listing.sort
+	if backward {
+		// Para voltar, a listagem é percorrida na ordem inversa e a página é desvirada no final
+		order = utils.ReverseSort(order)
+	}
+
+	db := usersQuery(r).Preload("Role").Scopes(listing.filters)
+	if listing.cursor != nil {
+		condition, args := utils.KeysetCondition(order, listing.position)
+		db = db.Where(condition, args...)
+	}
+
+	// Um registro a mais indica se há outra página na direção percorrida
+	var users []models.User
+	if err := db.Order(utils.OrderClause(order)).Limit(limit + 1).Find(&users).Error; err != nil {
+		return nil, "", "", err
+	}
+	hasMore := len(users) > limit
+	if hasMore {
+		users = users[:limit]
+	}
+	if backward {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+	}
+	if len(users) == 0 {
+		return users, "", "", nil
+	}
+
+	var next, prev string
+	first, last := users[0], users[len(users)-1]
+	if backward {
+		next = userCursor(listing.sort, last, false)
+		if hasMore {
+			prev = userCursor(listing.sort, first, true)
+		}
+	} else {
+		if hasMore {
+			next = userCursor(listing.sort, last, false)
+		}
+		if listing.cursor != nil {
+			prev = userCursor(listing.sort, first, true)
+		}
+	}
+	return users, next, prev, nil
+}
+
+// userCursor gera o cursor da posição do usuário na ordenação
+func userCursor(sort []utils.SortField, user models.User, backward bool) string {
+	values := make([]string, len(sort))
+	for i, field := range sort {
+		switch field.Name {
+		case "id":
+			values[i] = strconv.FormatUint(uint64(user.ID), 10)
+		case "name":
+			values[i] = user.Name
+		case "email":
+			values[i] = user.Email
+		case "created_at":
+			values[i] = user.CreatedAt.Format(time.RFC3339Nano)
+		case "updated_at":
+			values[i] = user.UpdatedAt.Format(time.RFC3339Nano)
+		}
+	}
+	return utils.EncodeCursor(utils.Cursor{Sort: utils.SortString(sort), Values: values, Backward: backward})
+}
+
+// parseUserCursorValues converte os valores do cursor para os tipos das colunas de ordenação
+func parseUserCursorValues(sort []utils.SortField, values []string) ([]interface{}, error) {
+	if len(values) != len(sort) {
+		return nil, utils.ErrInvalidCursor
+	}
+	position := make([]interface{}, len(sort))
+	for i, field := range sort {
+		switch field.Name {
+		case "id":
+			id, err := strconv.ParseUint(values[i], 10, 32)
+			if err != nil {
+				return nil, utils.ErrInvalidCursor
+			}
+			position[i] = id
+		case "created_at", "updated_at":
+			moment, err := time.Parse(time.RFC3339Nano, values[i])
+			if err != nil {
+				return nil, utils.ErrInvalidCursor
+			}
+			position[i] = moment
+		default:
+			position[i] = values[i]
+		}
+	}
+	return position, nil
+}
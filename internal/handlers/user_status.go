@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// maxStatusReasonLength é o tamanho máximo do motivo de uma mudança de situação
+const maxStatusReasonLength = 500
+
+// SetUserStatus altera a situação da conta de um usuário
+// @Summary Altera a situação da conta
+// @Description Ativa, suspende, bane ou deixa pendente a conta do usuário. Suspender ou banir exige um motivo.
+// @Description Contas que não estão ativas não fazem login e têm as sessões revogadas; os tokens já emitidos são
+// @Description recusados. Não é possível alterar a própria conta, a de quem tem capacidades que quem chama não
+// @Description possui, nem desativar o último administrador. Em uma organização, apenas usuários que não pertencem
+// @Description a outras organizações nem administram o escopo global podem ser alterados.
+// @Tags users
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID do usuário"
+// @Param request body models.UserStatusRequest true "Nova situação e motivo"
+// @Success 200 {object} models.AdminUserResponse "Usuário atualizado"
+// @Failure 400 {string} string "ID ou dados inválidos"
+// @Failure 403 {string} string "Não é possível alterar a própria conta ou a de quem tem mais capacidades"
+// @Failure 404 {string} string "Usuário não encontrado"
+// @Failure 409 {string} string "Último administrador ou conta usada fora da organização"
+// @Failure 422 {object} models.ValidationErrorResponse "Situação ou motivo inválidos"
+// @Router /admin/users/{id}/status [put]
+func SetUserStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var request models.UserStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	request.Reason = strings.TrimSpace(request.Reason)
+	errs := validator.Errors{}
+	switch request.Status {
+	case models.UserActive, models.UserPending:
+	case models.UserSuspended, models.UserBanned:
+		if request.Reason == "" {
+			errs.Add("reason", "Informe o motivo")
+		}
+	default:
+		errs.Add("status", "Situação inválida: use active, pending, suspended ou banned")
+	}
+	if utf8.RuneCountInString(request.Reason) > maxStatusReasonLength {
+		errs.Add("reason", "O motivo deve ter no máximo 500 caracteres")
+	}
+	if !errs.Empty() {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+	if !ok {
+		http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+		return
+	}
+
+	var user models.User
+	if err := usersQuery(r).Preload("Role").First(&user, id).Error; err != nil {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+	if user.ID == claims.UserID {
+		http.Error(w, "Não é possível alterar a situação da própria conta", http.StatusForbidden)
+		return
+	}
+
+	// A situação vale para a conta inteira: uma organização não altera a de quem também é membro de outras
+	// ou administra o escopo global
+	organizationID := callerOrg(r)
+	outside, err := accountOutsideCaller(r, user)
+	if err != nil {
+		http.Error(w, "Erro ao alterar a situação da conta", http.StatusInternalServerError)
+		return
+	}
+	if outside {
+		http.Error(w, "O usuário pertence a outras organizações ou administra o escopo global", http.StatusConflict)
+		return
+	}
+
+	// Só altera a situação de quem não tem mais acesso do que quem chama
+	caller, err := utils.ResolveClaims(claims)
+	if err != nil {
+		http.Error(w, "Role não encontrada", http.StatusForbidden)
+		return
+	}
+	targetRoleID := user.RoleID
+	if organizationID != 0 {
+		membership, err := utils.FindMembership(user.ID, organizationID)
+		if err != nil {
+			http.Error(w, "Erro ao buscar vínculo com a organização", http.StatusInternalServerError)
+			return
+		}
+		targetRoleID = membership.RoleID
+	}
+	target, err := rbac.ResolveUser(user.ID, organizationID, targetRoleID)
+	if err != nil {
+		http.Error(w, "Erro ao alterar a situação da conta", http.StatusInternalServerError)
+		return
+	}
+	for _, capability := range target.Capabilities {
+		if !rbac.Covers(caller.Capabilities, capability) {
+			http.Error(w, "Acesso negado: o usuário tem capacidades que você não possui", http.StatusForbidden)
+			return
+		}
+	}
+
+	// A marca no Redis é gravada antes da confirmação, para que uma falha nela desfaça a alteração
+	now := time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return utils.GuardLastAdmin(tx, organizationID, func() error {
+			updates := map[string]interface{}{
+				"status":            request.Status,
+				"status_reason":     request.Reason,
+				"status_changed_at": now,
+			}
+			if err := tx.Model(&user).Updates(updates).Error; err != nil {
+				return err
+			}
+			return utils.SyncUserStatus(user.ID, request.Status)
+		})
+	})
+	if err != nil {
+		// Em caso de erro a marca volta a refletir a situação gravada no banco
+		if syncErr := utils.SyncUserStatus(user.ID, user.Status); syncErr != nil {
+			log.Printf("Erro ao restaurar a situação da conta do usuário %d: %v", user.ID, syncErr)
+		}
+	}
+	if !writeRoleError(w, err, "Erro ao alterar a situação da conta") {
+		return
+	}
+	user.Status = request.Status
+	user.StatusReason = request.Reason
+	user.StatusChangedAt = &now
+
+	// As sessões abertas deixam de valer; novos logins são recusados enquanto a conta não for reativada
+	if !user.Active() {
+		if err := utils.RevokeUserSessions(user.ID); err != nil {
+			log.Printf("Erro ao revogar as sessões do usuário %d: %v", user.ID, err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewAdminUserResponse(user))
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/pkg/validator"
+)
+
+// writeValidationErrors responde com status 422 e os erros de validação agrupados por campo
+func writeValidationErrors(w http.ResponseWriter, errs validator.Errors) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(models.ValidationErrorResponse{
+		Message: "Dados inválidos",
+		Errors:  errs,
+	})
+}
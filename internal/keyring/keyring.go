@@ -0,0 +1,307 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Algoritmos de assinatura suportados
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+)
+
+// activeFile é o arquivo, dentro do diretório de chaves, que contém o kid da chave ativa
+const activeFile = "active"
+
+// Key representa uma chave de assinatura identificada pelo seu kid
+type Key struct {
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+}
+
+// Keyring contém a chave ativa, usada para assinar, e as demais chaves (aposentadas ou publicadas e ainda
+// não ativadas), usadas apenas para verificar
+type Keyring struct {
+	Active *Key
+	keys   map[string]*Key
+}
+
+// JWK representa uma chave pública no formato JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS representa o conjunto de chaves públicas publicado em /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Load carrega todas as chaves (<kid>.pem) do diretório e identifica a chave ativa
+func Load(dir string) (*Keyring, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar chaves: %v", err)
+	}
+
+	ring := &Keyring{keys: make(map[string]*Key)}
+	for _, file := range files {
+		key, err := readKey(file)
+		if err != nil {
+			return nil, err
+		}
+		ring.keys[key.ID] = key
+	}
+
+	if len(ring.keys) == 0 {
+		return ring, nil
+	}
+
+	activeID, err := os.ReadFile(filepath.Join(dir, activeFile))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chave ativa: %v", err)
+	}
+	active, ok := ring.keys[strings.TrimSpace(string(activeID))]
+	if !ok {
+		return nil, fmt.Errorf("chave ativa %q não encontrada em %s", strings.TrimSpace(string(activeID)), dir)
+	}
+	ring.Active = active
+
+	return ring, nil
+}
+
+// Generate gera uma nova chave para o algoritmo informado
+func Generate(algorithm string) (*Key, error) {
+	var (
+		privateKey crypto.Signer
+		err        error
+	)
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("algoritmo de assinatura não suportado: %s", algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar chave %s: %v", algorithm, err)
+	}
+
+	return newKey(privateKey)
+}
+
+// Add gera uma nova chave e a grava no diretório sem ativá-la: ela passa a ser publicada no JWKS e aceita na
+// verificação, mas só assina depois de Activate. Em um diretório sem chave ativa, a nova chave é ativada.
+func Add(dir, algorithm string) (*Key, error) {
+	key, err := Generate(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório de chaves: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar chave: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, key.ID+".pem"), data, 0o600); err != nil {
+		return nil, fmt.Errorf("erro ao gravar chave: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, activeFile)); os.IsNotExist(err) {
+		if err := writeActive(dir, key.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// Activate torna ativa uma chave já gravada no diretório; a chave anterior passa a ser aposentada.
+// Com várias instâncias, a chave deve ser publicada (Add) e distribuída antes, para que os tokens assinados
+// por ela sejam aceitos por todas as instâncias e pelos caches de JWKS.
+func Activate(dir, kid string) (*Key, error) {
+	ring, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := ring.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("chave %q não encontrada", kid)
+	}
+	if err := writeActive(dir, kid); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Rotate gera uma nova chave e a ativa imediatamente (Add seguido de Activate). Serve para uma única instância
+// ou para a chave inicial; com várias instâncias, use Add e Activate em etapas separadas.
+func Rotate(dir, algorithm string) (*Key, error) {
+	key, err := Add(dir, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return Activate(dir, key.ID)
+}
+
+// Retire remove definitivamente uma chave que não está ativa; tokens assinados por ela deixam de ser aceitos
+func Retire(dir, kid string) error {
+	ring, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	if _, ok := ring.keys[kid]; !ok {
+		return fmt.Errorf("chave %q não encontrada", kid)
+	}
+	if ring.Active != nil && ring.Active.ID == kid {
+		return fmt.Errorf("a chave %q está ativa e não pode ser removida", kid)
+	}
+
+	if err := os.Remove(filepath.Join(dir, kid+".pem")); err != nil {
+		return fmt.Errorf("erro ao remover chave: %v", err)
+	}
+	return nil
+}
+
+// Key retorna a chave com o kid informado
+func (k *Keyring) Key(kid string) (*Key, bool) {
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// Keys retorna todas as chaves do keyring ordenadas pelo kid
+func (k *Keyring) Keys() []*Key {
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
+
+// JWKS retorna as chaves públicas de todas as chaves do keyring
+func (k *Keyring) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range k.Keys() {
+		jwks.Keys = append(jwks.Keys, key.JWK())
+	}
+	return jwks
+}
+
+// SigningMethod retorna o método de assinatura JWT correspondente à chave
+func (k *Key) SigningMethod() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// PublicKey retorna a chave pública usada na verificação dos tokens
+func (k *Key) PublicKey() crypto.PublicKey {
+	return k.PrivateKey.Public()
+}
+
+// JWK retorna a representação pública da chave no formato JWK
+func (k *Key) JWK() JWK {
+	jwk := JWK{Use: "sig", Alg: k.Algorithm, Kid: k.ID}
+	switch pub := k.PublicKey().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	}
+	return jwk
+}
+
+// writeActive grava o kid da chave ativa
+func writeActive(dir, kid string) error {
+	if err := os.WriteFile(filepath.Join(dir, activeFile), []byte(kid+"\n"), 0o600); err != nil {
+		return fmt.Errorf("erro ao ativar chave: %v", err)
+	}
+	return nil
+}
+
+// readKey lê uma chave privada PKCS#8 em formato PEM
+func readKey(file string) (*Key, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chave %s: %v", file, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("chave %s não está no formato PEM", file)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decodificar chave %s: %v", file, err)
+	}
+
+	privateKey, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("tipo de chave não suportado em %s", file)
+	}
+
+	return newKey(privateKey)
+}
+
+// newKey identifica o algoritmo da chave e calcula o seu kid a partir da chave pública
+func newKey(privateKey crypto.Signer) (*Key, error) {
+	var algorithm string
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		algorithm = AlgorithmRS256
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("curva ECDSA não suportada: %s", k.Curve.Params().Name)
+		}
+		algorithm = AlgorithmES256
+	default:
+		return nil, fmt.Errorf("tipo de chave não suportado")
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar chave pública: %v", err)
+	}
+	sum := sha256.Sum256(der)
+
+	return &Key{
+		ID:         base64.RawURLEncoding.EncodeToString(sum[:12]),
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+	}, nil
+}
@@ -0,0 +1,222 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/settings"
+)
+
+// Message representa um e-mail em texto puro
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer é a interface de envio de e-mails usada pela aplicação
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// Default é o mailer usado pela aplicação; main o substitui conforme MAIL_DRIVER
+var Default Mailer = &LogMailer{From: "no-reply@gotham.local"}
+
+// Send envia a mensagem pelo mailer padrão
+func Send(msg Message) error {
+	return Default.Send(msg)
+}
+
+// pending acompanha os envios feitos em segundo plano por Async
+var pending sync.WaitGroup
+
+// Async executa send em segundo plano, para que a resposta não dependa do tempo de envio: nas rotas públicas,
+// a demora denunciaria se o e-mail informado está cadastrado. Os erros devem ser tratados dentro de send.
+func Async(send func()) {
+	pending.Add(1)
+	go func() {
+		defer pending.Done()
+		send()
+	}()
+}
+
+// Wait aguarda o término dos envios iniciados por Async
+func Wait() {
+	pending.Wait()
+}
+
+// New cria o mailer correspondente ao driver configurado (smtp, log ou file)
+func New(config *settings.Config) (Mailer, error) {
+	from := (&mail.Address{Name: config.Mail.FromName, Address: config.Mail.FromAddress}).String()
+
+	switch config.Mail.Driver {
+	case "smtp":
+		return &SMTPMailer{
+			Host:       config.Mail.Host,
+			Port:       config.Mail.Port,
+			Username:   config.Mail.Username,
+			Password:   config.Mail.Password,
+			Encryption: config.Mail.Encryption,
+			From:       from,
+			Timeout:    config.Mail.Timeout,
+		}, nil
+	case "log":
+		return &LogMailer{From: from}, nil
+	case "file":
+		return &FileMailer{Dir: config.Mail.FileDir, From: from}, nil
+	default:
+		return nil, fmt.Errorf("driver de e-mail não suportado: %s", config.Mail.Driver)
+	}
+}
+
+// SMTPMailer envia e-mails por um servidor SMTP.
+// Encryption aceita "tls" (TLS implícito) ou vazio/"starttls" (STARTTLS quando o servidor oferecer).
+type SMTPMailer struct {
+	Host       string
+	Port       string
+	Username   string
+	Password   string
+	Encryption string
+	From       string
+	Timeout    time.Duration // Limite da sessão inteira; zero usa defaultSMTPTimeout
+}
+
+// defaultSMTPTimeout limita a sessão SMTP quando o mailer não define Timeout: sem limite, um servidor que não
+// responde prenderia para sempre os envios feitos em segundo plano (ver Async)
+const defaultSMTPTimeout = 30 * time.Second
+
+// Send envia a mensagem pelo servidor SMTP
+func (m *SMTPMailer) Send(msg Message) error {
+	addr := net.JoinHostPort(m.Host, m.Port)
+	sender, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("remetente inválido: %v", err)
+	}
+
+	timeout := m.Timeout
+	if timeout <= 0 {
+		timeout = defaultSMTPTimeout
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	if m.Encryption == "tls" || m.Encryption == "ssl" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: m.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao servidor SMTP: %v", err)
+	}
+	// O prazo vale para toda a sessão, inclusive depois do STARTTLS, que usa a mesma conexão
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao configurar a conexão SMTP: %v", err)
+	}
+
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao iniciar sessão SMTP: %v", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && m.Encryption != "tls" && m.Encryption != "ssl" {
+		if err := client.StartTLS(&tls.Config{ServerName: m.Host}); err != nil {
+			return fmt.Errorf("erro ao iniciar STARTTLS: %v", err)
+		}
+	}
+
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return fmt.Errorf("erro na autenticação SMTP: %v", err)
+		}
+	}
+
+	if err := client.Mail(sender.Address); err != nil {
+		return fmt.Errorf("erro ao definir remetente: %v", err)
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("erro ao definir destinatário %s: %v", to, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar envio da mensagem: %v", err)
+	}
+	if _, err := writer.Write(msg.Bytes(m.From)); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %v", err)
+	}
+
+	return client.Quit()
+}
+
+// LogMailer apenas registra os e-mails no log (útil em desenvolvimento)
+type LogMailer struct {
+	From string
+}
+
+// Send registra a mensagem no log
+func (m *LogMailer) Send(msg Message) error {
+	log.Printf("E-mail para %s: %s\n%s", strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+	return nil
+}
+
+// FileMailer grava cada e-mail como um arquivo .eml no diretório informado (útil em testes)
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+// Send grava a mensagem em um novo arquivo .eml
+func (m *FileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de e-mails: %v", err)
+	}
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), hex.EncodeToString(suffix))
+
+	if err := os.WriteFile(filepath.Join(m.Dir, name), msg.Bytes(m.From), 0o644); err != nil {
+		return fmt.Errorf("erro ao gravar e-mail: %v", err)
+	}
+	return nil
+}
+
+// Bytes monta a mensagem no formato RFC 5322, com corpo em quoted-printable UTF-8
+func (msg Message) Bytes(from string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from + "\r\n")
+	buf.WriteString("To: " + strings.Join(msg.To, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	qp.Write([]byte(msg.Body))
+	qp.Close()
+
+	return buf.Bytes()
+}
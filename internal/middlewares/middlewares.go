@@ -6,8 +6,8 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
-	"github.com/jeffemart/Gotham/internal/database"
-	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/policy"
+	"github.com/jeffemart/Gotham/internal/rbac"
 	"github.com/jeffemart/Gotham/internal/utils"
 )
 
@@ -24,15 +24,27 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		// Extrair o token do cabeçalho
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		// Parse e validação do token
-		token, claims, err := utils.ParseToken(tokenString)
-		if err != nil || token == nil || !token.Valid {
-			http.Error(w, "Token inválido ou expirado", http.StatusUnauthorized)
+		// Parse e validação do token, incluindo a verificação de revogação no Redis
+		claims, err := utils.ValidateToken(tokenString)
+		if err != nil {
+			http.Error(w, "Token inválido, expirado ou revogado", http.StatusUnauthorized)
 			return
 		}
 
-		// Adicionar o Claims ao contexto para que as próximas funções possam acessar
+		// Contas suspensas, banidas ou pendentes não usam os tokens já emitidos
+		status, err := utils.InactiveStatus(claims.UserID)
+		if err != nil {
+			http.Error(w, "Erro ao verificar a situação da conta", http.StatusInternalServerError)
+			return
+		}
+		if status != "" {
+			http.Error(w, utils.InactiveAccountMessage(status), http.StatusForbidden)
+			return
+		}
+
+		// Adicionar o Claims e o token ao contexto para que as próximas funções possam acessar
 		ctx := context.WithValue(r.Context(), utils.RoleKey, claims)
+		ctx = context.WithValue(ctx, utils.TokenKey, tokenString)
 		r = r.WithContext(ctx)
 
 		// Chamar o próximo handler na cadeia
@@ -40,6 +52,61 @@ func AuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// AuthorizationMiddleware aplica a política de acesso à rota encontrada pelo mux.
+// Rotas públicas passam direto; as demais exigem um token válido e as roles ou capacidades da regra.
+// Rotas sem regra são negadas, para que uma rota nova não fique exposta por engano.
+func AuthorizationMiddleware(p *policy.Policy) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rule, found := routeRule(p, r)
+			if !found {
+				http.Error(w, "Acesso negado: rota sem política de acesso", http.StatusForbidden)
+				return
+			}
+			if rule.Public {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims, ok := r.Context().Value(utils.RoleKey).(*utils.Claims)
+				if !ok {
+					http.Error(w, "Erro ao obter informações do usuário", http.StatusInternalServerError)
+					return
+				}
+
+				// Rotas que afetam o sistema inteiro não ficam ao alcance dos administradores de uma organização
+				if rule.GlobalOnly && claims.OrgID != 0 {
+					http.Error(w, "Acesso negado: disponível apenas no escopo global", http.StatusForbidden)
+					return
+				}
+
+				if len(rule.Roles) == 0 && len(rule.Capabilities) == 0 {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				role, err := utils.ResolveClaims(claims)
+				if err != nil {
+					http.Error(w, "Role não encontrada", http.StatusForbidden)
+					return
+				}
+
+				if len(rule.Roles) > 0 && !hasAnyRole(role, rule.Roles) {
+					http.Error(w, "Acesso negado: você não tem a role necessária para acessar essa rota", http.StatusForbidden)
+					return
+				}
+				if !rbac.HasCapabilities(role.Capabilities, rule.Capabilities...) {
+					http.Error(w, "Acesso negado: capacidades insuficientes", http.StatusForbidden)
+					return
+				}
+
+				next.ServeHTTP(w, r)
+			})).ServeHTTP(w, r)
+		})
+	}
+}
+
 // RoleMiddleware verifica se o usuário tem uma das roles necessárias para acessar a rota
 func RoleMiddleware(roles ...string) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
@@ -51,52 +118,19 @@ func RoleMiddleware(roles ...string) mux.MiddlewareFunc {
 				return
 			}
 
-			// Recuperar a role do banco de dados com as permissões associadas
-			var role models.Role
-			if err := database.DB.Preload("Permissions").First(&role, claims.RoleID).Error; err != nil {
+			// Recuperar a role do banco de dados com a hierarquia e os grupos resolvidos
+			role, err := utils.ResolveClaims(claims)
+			if err != nil {
 				http.Error(w, "Role não encontrada", http.StatusForbidden)
 				return
 			}
 
-			// Verificar se a role do usuário corresponde a algum dos papéis fornecidos
-			roleFound := false
-			for _, allowedRole := range roles {
-				if role.Name == allowedRole {
-					roleFound = true
-					break
-				}
-			}
-
 			// Se a role não for encontrada, retorna erro de acesso negado
-			if !roleFound {
+			if !hasAnyRole(role, roles) {
 				http.Error(w, "Acesso negado: você não tem a role necessária para acessar essa rota", http.StatusForbidden)
 				return
 			}
 
-			// Verificar as permissões dinâmicas baseadas na ação da rota
-			permissionGranted := false
-			switch r.Method {
-			case http.MethodDelete:
-				// Para DELETE, precisa de permissão de admin (ID 1)
-				if claims.RoleID == 1 {
-					permissionGranted = true
-				}
-			case http.MethodPut, http.MethodPatch:
-				// Para PUT e PATCH, precisa de permissão de agent (ID 2) ou admin (ID 1)
-				if claims.RoleID == 1 || claims.RoleID == 2 {
-					permissionGranted = true
-				}
-			default:
-				// Para outros métodos, concede permissão
-				permissionGranted = true
-			}
-
-			// Se a permissão não for concedida, retorna erro
-			if !permissionGranted {
-				http.Error(w, "Acesso negado: você não tem permissão para acessar essa rota", http.StatusForbidden)
-				return
-			}
-
 			// Chamar o próximo handler na cadeia
 			next.ServeHTTP(w, r)
 		})
@@ -114,30 +148,15 @@ func CapabilityMiddleware(requiredCapabilities ...string) mux.MiddlewareFunc {
 				return
 			}
 
-			// Recuperar a role do banco de dados
-			var role models.Role
-			if err := database.DB.First(&role, claims.RoleID).Error; err != nil {
+			// Recuperar a role do banco de dados, com as capacidades herdadas das roles pai e recebidas dos grupos
+			role, err := utils.ResolveClaims(claims)
+			if err != nil {
 				http.Error(w, "Role não encontrada", http.StatusForbidden)
 				return
 			}
 
 			// Verificar se o usuário tem todas as capacidades necessárias
-			hasAllCapabilities := true
-			for _, requiredCap := range requiredCapabilities {
-				hasCapability := false
-				for _, userCap := range role.Capabilities {
-					if userCap == requiredCap || userCap == "*" { // "*" representa acesso total
-						hasCapability = true
-						break
-					}
-				}
-				if !hasCapability {
-					hasAllCapabilities = false
-					break
-				}
-			}
-
-			if !hasAllCapabilities {
+			if !rbac.HasCapabilities(role.Capabilities, requiredCapabilities...) {
 				http.Error(w, "Acesso negado: capacidades insuficientes", http.StatusForbidden)
 				return
 			}
@@ -146,3 +165,28 @@ func CapabilityMiddleware(requiredCapabilities ...string) mux.MiddlewareFunc {
 		})
 	}
 }
+
+// routeRule busca a regra da rota encontrada pelo mux para a requisição
+func routeRule(p *policy.Policy, r *http.Request) (policy.Rule, bool) {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return policy.Rule{}, false
+	}
+	path, err := route.GetPathTemplate()
+	if err != nil {
+		return policy.Rule{}, false
+	}
+	return p.Match(r.Method, path)
+}
+
+// hasAnyRole verifica se a role do usuário, uma role da qual ela herda ou uma role recebida de um grupo é uma das roles informadas
+func hasAnyRole(role *rbac.ResolvedRole, roles []string) bool {
+	for _, allowedRole := range roles {
+		for _, name := range role.Chain {
+			if name == allowedRole {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -6,28 +6,112 @@ import (
 	"gorm.io/gorm"
 )
 
-// User representa um usuário no banco de dados
+// User representa um usuário no banco de dados. As respostas da API usam UserResponse ou AdminUserResponse;
+// o hash da senha nunca é serializado. A exclusão é lógica (deleted_at) até a remoção definitiva.
 type User struct {
 	gorm.Model
 	Name     string `gorm:"size:255;not null"`
-	Email    string `gorm:"size:255;not null;unique"`
-	Password string `gorm:"size:255;not null"`
+	Email    string `gorm:"size:255;not null"` // Único entre os usuários não excluídos (ver migrations)
+	Password string `gorm:"size:255;not null" json:"-"`
 	RoleID   uint   `gorm:"not null"`
 	Role     Role   `gorm:"foreignKey:RoleID"`
+
+	// Data de verificação do e-mail; nil enquanto o usuário não confirmar o endereço
+	VerifiedAt *time.Time
+
+	// MFA (TOTP); o segredo nunca é serializado nas respostas
+	MFAEnabled bool   `gorm:"not null;default:false"`
+	MFASecret  string `gorm:"size:64" json:"-"`
+
+	// Situação da conta (UserActive, UserPending, UserSuspended ou UserBanned); apenas contas ativas fazem
+	// login e usam os tokens emitidos
+	Status          string `gorm:"size:20;not null;default:'active';index"`
+	StatusReason    string `gorm:"size:500"`
+	StatusChangedAt *time.Time
+}
+
+// Situações da conta do usuário
+const (
+	UserActive    = "active"
+	UserPending   = "pending"   // Aguardando a ativação por um administrador
+	UserSuspended = "suspended" // Bloqueada temporariamente
+	UserBanned    = "banned"    // Bloqueada definitivamente
+)
+
+// Active indica se a conta pode fazer login e usar os tokens emitidos
+func (u User) Active() bool {
+	return u.Status == UserActive
 }
 
 type Role struct {
 	gorm.Model
-	Name         string       `gorm:"size:255;not null;unique"`
-	Permissions  []Permission `gorm:"many2many:role_permissions"`
-	Capabilities []string     `gorm:"type:text[]"`
+	Name           string       `gorm:"size:255;not null"` // Único entre as roles globais e as da organização (ver migrations)
+	Permissions    []Permission `gorm:"many2many:role_permissions"`
+	Capabilities   []string     `gorm:"type:text[]"`
+	RequireMFA     bool         `gorm:"not null;default:false"` // Exige MFA no login dos usuários com esta role
+	ParentID       *uint        `gorm:"index"`                  // Role da qual as capacidades e permissões são herdadas
+	OrganizationID *uint        `gorm:"index"`                  // Organização dona da role; nil para roles globais
 }
 
-type Permission struct {
+// Organization representa uma organização (tenant) atendida pela instância
+type Organization struct {
 	gorm.Model
 	Name string `gorm:"size:255;not null;unique"`
 }
 
+// Membership vincula um usuário a uma organização, com a role que ele tem nela
+type Membership struct {
+	ID             uint         `gorm:"primaryKey"`
+	UserID         uint         `gorm:"not null;uniqueIndex:idx_memberships_user_organization"`
+	OrganizationID uint         `gorm:"not null;uniqueIndex:idx_memberships_user_organization;index"`
+	RoleID         uint         `gorm:"not null"`
+	User           User         `gorm:"foreignKey:UserID"`
+	Organization   Organization `gorm:"foreignKey:OrganizationID"`
+	Role           Role         `gorm:"foreignKey:RoleID"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Group reúne usuários que recebem as mesmas roles, além da role direta de cada um
+type Group struct {
+	gorm.Model
+	Name           string `gorm:"size:255;not null"` // Único entre os grupos globais e entre os da organização (ver migrations)
+	OrganizationID *uint  `gorm:"index"`             // Organização dona do grupo; nil para grupos globais
+	Roles          []Role `gorm:"many2many:group_roles"`
+	Users          []User `gorm:"many2many:group_users" json:"-"` // Listados em GET /admin/groups/{id}/members
+}
+
+// RoleGrant concede temporariamente uma role ao usuário, além da role direta e dos grupos.
+// A concessão é solicitada, aprovada por outro administrador e só vale entre StartsAt e ExpiresAt.
+type RoleGrant struct {
+	gorm.Model
+	UserID         uint      `gorm:"not null;index"`
+	RoleID         uint      `gorm:"not null;index"`
+	OrganizationID *uint     `gorm:"index"` // Organização em que a concessão vale; nil para o escopo global
+	StartsAt       time.Time `gorm:"not null"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+	Reason         string    `gorm:"size:500"`
+	Status         string    `gorm:"size:20;not null;index"` // Ver GrantPending e as demais constantes
+	RequestedByID  *uint     // Quem solicitou; nil quando o usuário foi removido definitivamente
+	DecidedByID    *uint     // Administrador que aprovou, rejeitou ou revogou; nil também quando ele foi removido
+	DecidedAt      *time.Time
+	User           User `gorm:"foreignKey:UserID" json:"-"`
+	Role           Role `gorm:"foreignKey:RoleID"`
+}
+
+// Situações de uma concessão temporária de role
+const (
+	GrantPending  = "pending"
+	GrantApproved = "approved"
+	GrantRejected = "rejected"
+	GrantRevoked  = "revoked"
+)
+
+type Permission struct {
+	gorm.Model
+	Name string `gorm:"size:255;not null"` // Único entre as permissões não removidas (ver migrations)
+}
+
 type RolePermission struct {
 	RoleID       uint `gorm:"primaryKey"`
 	PermissionID uint `gorm:"primaryKey"`
@@ -35,25 +119,439 @@ type RolePermission struct {
 	DeletedAt    gorm.DeletedAt `gorm:"index"`
 }
 
+// MFARecoveryCode representa um código de recuperação de MFA de uso único (armazenado como hash)
+type MFARecoveryCode struct {
+	gorm.Model
+	UserID   uint   `gorm:"not null;index"`
+	CodeHash string `gorm:"size:64;not null;index"`
+	UsedAt   *time.Time
+}
+
+// PasswordResetToken representa um token de redefinição de senha de uso único (armazenado como hash)
+type PasswordResetToken struct {
+	gorm.Model
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
+
 // DTOs (Data Transfer Objects)
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email          string `json:"email"`
+	Password       string `json:"password"`
+	OrganizationID uint   `json:"organization_id"` // Organização em que a sessão é aberta; 0 para o escopo global
 }
 
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
-// PaginatedResponse representa uma resposta paginada
+// TokenResponse representa o par de tokens retornado na autenticação
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// MFAChallengeResponse é retornado pelo login quando a senha está correta mas o MFA é exigido
+type MFAChallengeResponse struct {
+	MFARequired        bool   `json:"mfa_required"`
+	MFAToken           string `json:"mfa_token"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+}
+
+// MFALoginRequest conclui o login em duas etapas com um código TOTP ou um código de recuperação
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// MFALoginResponse contém os tokens e, quando o cadastro do MFA é concluído no login, os códigos de recuperação
+type MFALoginResponse struct {
+	TokenResponse
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// MFAEnrollRequest inicia o cadastro do MFA durante o login (roles que exigem MFA)
+type MFAEnrollRequest struct {
+	MFAToken string `json:"mfa_token"`
+}
+
+// MFAEnrollmentResponse contém o segredo e a URI otpauth:// a serem cadastrados no aplicativo autenticador
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFACodeRequest confirma uma operação de MFA com um código TOTP ou um código de recuperação
+type MFACodeRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// MFARecoveryCodesResponse contém os códigos de recuperação, exibidos apenas uma vez
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ForgotPasswordRequest solicita o envio do link de redefinição de senha
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest redefine a senha usando o token recebido por e-mail
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// VerifyEmailRequest confirma o e-mail com o token recebido no link de verificação
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ResendVerificationRequest solicita o reenvio do link de verificação de e-mail
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+// LockoutStatus representa o estado do bloqueio de login de uma conta ou de um IP
+type LockoutStatus struct {
+	Scope      string `json:"scope"`
+	Target     string `json:"target"`
+	Failures   int64  `json:"failures"`
+	Locked     bool   `json:"locked"`
+	RetryAfter int64  `json:"retry_after"`
+}
+
+// Session representa uma sessão ativa (família de refresh tokens) de um usuário
+type Session struct {
+	ID             string    `json:"id"`
+	UserID         uint      `json:"user_id"`
+	OrganizationID uint      `json:"organization_id,omitempty"`
+	IP             string    `json:"ip"`
+	UserAgent      string    `json:"user_agent"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastUsedAt     time.Time `json:"last_used_at"`
+	Current        bool      `json:"current"`
+}
+
+// RoleRequest cria ou atualiza uma role
+type RoleRequest struct {
+	Name          string   `json:"name"`
+	Capabilities  []string `json:"capabilities"`
+	PermissionIDs []uint   `json:"permission_ids"`
+	RequireMFA    *bool    `json:"require_mfa"`
+	ParentID      *uint    `json:"parent_id"` // 0 remove a role pai
+}
+
+// RoleCapabilitiesRequest substitui as capacidades de uma role
+type RoleCapabilitiesRequest struct {
+	Capabilities []string `json:"capabilities"`
+}
+
+// RolePermissionsRequest substitui as permissões de uma role
+type RolePermissionsRequest struct {
+	PermissionIDs []uint `json:"permission_ids"`
+}
+
+// PermissionRequest cria uma permissão
+type PermissionRequest struct {
+	Name string `json:"name"`
+}
+
+// OrganizationRequest cria ou renomeia uma organização
+type OrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+// MembershipRequest adiciona um usuário a uma organização ou troca a sua role nela
+type MembershipRequest struct {
+	RoleID uint `json:"role_id"`
+}
+
+// GroupRequest cria ou altera um grupo; role_ids substitui as roles do grupo
+type GroupRequest struct {
+	Name    string `json:"name"`
+	RoleIDs []uint `json:"role_ids"`
+}
+
+// GrantRequest solicita a concessão temporária de uma role. Sem starts_at, a concessão vale a partir da aprovação.
+type GrantRequest struct {
+	UserID    uint       `json:"user_id"` // Usado apenas em /admin/grants; em /me/grants é o próprio usuário
+	RoleID    uint       `json:"role_id"`
+	StartsAt  *time.Time `json:"starts_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	Reason    string     `json:"reason"`
+}
+
+// AuthzSubject identifica para quem a decisão de acesso é tomada: um access token ou um usuário.
+// Vazio, vale o próprio usuário autenticado.
+type AuthzSubject struct {
+	Token          string `json:"token,omitempty"`
+	UserID         uint   `json:"user_id,omitempty"`
+	OrganizationID uint   `json:"organization_id,omitempty"` // Com user_id, organização em que a decisão vale
+}
+
+// AuthzResource é o recurso sobre o qual a ação é exercida; o dono define o escopo (own ou any) exigido
+type AuthzResource struct {
+	Type    string `json:"type,omitempty"`
+	ID      string `json:"id,omitempty"`
+	OwnerID uint   `json:"owner_id"`
+}
+
+// AuthzCheck é uma ação ("verbo:recurso[:escopo]"), opcionalmente sobre um recurso
+type AuthzCheck struct {
+	Action   string         `json:"action"`
+	Resource *AuthzResource `json:"resource,omitempty"`
+}
+
+// AuthzCheckRequest pede a decisão de uma ação para o sujeito
+type AuthzCheckRequest struct {
+	Subject AuthzSubject `json:"subject"`
+	AuthzCheck
+}
+
+// AuthzBatchRequest pede a decisão de várias ações para o mesmo sujeito
+type AuthzBatchRequest struct {
+	Subject AuthzSubject `json:"subject"`
+	Checks  []AuthzCheck `json:"checks"`
+}
+
+// ProfileUpdateRequest altera o perfil do próprio usuário; apenas os campos informados são alterados.
+// A role não faz parte do perfil: ela só é trocada por quem tem manage:roles.
+type ProfileUpdateRequest struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+// ChangePasswordRequest troca a senha do próprio usuário mediante a senha atual
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// CreateUserRequest é o cadastro público de um usuário. A role não é informada: o usuário recebe a role
+// padrão (RBAC_DEFAULT_ROLE) e só quem tem manage:roles pode trocá-la.
+type CreateUserRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// UpdateUserRequest altera um usuário; campos vazios são mantidos. Trocar a role exige manage:roles.
+type UpdateUserRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	RoleID   uint   `json:"role_id"`
+}
+
+// RoleSummary identifica uma role nas respostas de usuários
+type RoleSummary struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserResponse é a visão pública de um usuário: o cadastro e quem só pode ver o próprio registro
+type UserResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdminUserResponse é a visão administrativa de um usuário, também usada no próprio perfil (/me)
+type AdminUserResponse struct {
+	UserResponse
+	RoleID     uint         `json:"role_id"`
+	Role       *RoleSummary `json:"role,omitempty"` // Presente quando a role foi carregada
+	VerifiedAt *time.Time   `json:"verified_at"`
+	MFAEnabled bool         `json:"mfa_enabled"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+
+	Status          string     `json:"status"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+}
+
+// DeletedUserResponse é a visão de um usuário na lixeira
+type DeletedUserResponse struct {
+	AdminUserResponse
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"` // Quando a remoção definitiva automática o alcança
+}
+
+// NewUserResponse monta a visão pública do usuário
+func NewUserResponse(user User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+// NewAdminUserResponse monta a visão administrativa do usuário
+func NewAdminUserResponse(user User) AdminUserResponse {
+	response := AdminUserResponse{
+		UserResponse: NewUserResponse(user),
+		RoleID:       user.RoleID,
+		VerifiedAt:   user.VerifiedAt,
+		MFAEnabled:   user.MFAEnabled,
+		UpdatedAt:    user.UpdatedAt,
+
+		Status:          user.Status,
+		StatusReason:    user.StatusReason,
+		StatusChangedAt: user.StatusChangedAt,
+	}
+	if user.Role.ID != 0 {
+		response.Role = &RoleSummary{ID: user.Role.ID, Name: user.Role.Name}
+	}
+	return response
+}
+
+// NewAdminUserResponses monta a visão administrativa de uma lista de usuários
+func NewAdminUserResponses(users []User) []AdminUserResponse {
+	responses := make([]AdminUserResponse, len(users))
+	for i, user := range users {
+		responses[i] = NewAdminUserResponse(user)
+	}
+	return responses
+}
+
+// OrganizationSummary identifica a organização de um vínculo
+type OrganizationSummary struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// MembershipResponse é a visão do vínculo de um usuário com uma organização. O usuário, a organização e a role
+// aparecem quando foram carregados.
+type MembershipResponse struct {
+	ID             uint                 `json:"id"`
+	UserID         uint                 `json:"user_id"`
+	User           *AdminUserResponse   `json:"user,omitempty"`
+	OrganizationID uint                 `json:"organization_id"`
+	Organization   *OrganizationSummary `json:"organization,omitempty"`
+	RoleID         uint                 `json:"role_id"`
+	Role           *RoleSummary         `json:"role,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// NewMembershipResponse monta a visão do vínculo
+func NewMembershipResponse(membership Membership) MembershipResponse {
+	response := MembershipResponse{
+		ID:             membership.ID,
+		UserID:         membership.UserID,
+		OrganizationID: membership.OrganizationID,
+		RoleID:         membership.RoleID,
+		CreatedAt:      membership.CreatedAt,
+		UpdatedAt:      membership.UpdatedAt,
+	}
+	if membership.User.ID != 0 {
+		user := NewAdminUserResponse(membership.User)
+		response.User = &user
+	}
+	if membership.Organization.ID != 0 {
+		response.Organization = &OrganizationSummary{ID: membership.Organization.ID, Name: membership.Organization.Name}
+	}
+	if membership.Role.ID != 0 {
+		response.Role = &RoleSummary{ID: membership.Role.ID, Name: membership.Role.Name}
+	}
+	return response
+}
+
+// NewMembershipResponses monta a visão de uma lista de vínculos
+func NewMembershipResponses(memberships []Membership) []MembershipResponse {
+	responses := make([]MembershipResponse, len(memberships))
+	for i, membership := range memberships {
+		responses[i] = NewMembershipResponse(membership)
+	}
+	return responses
+}
+
+// GrantResponse é a visão de uma concessão temporária de role; a role aparece quando foi carregada
+type GrantResponse struct {
+	ID             uint         `json:"id"`
+	UserID         uint         `json:"user_id"`
+	RoleID         uint         `json:"role_id"`
+	Role           *RoleSummary `json:"role,omitempty"`
+	OrganizationID *uint        `json:"organization_id"`
+	StartsAt       time.Time    `json:"starts_at"`
+	ExpiresAt      time.Time    `json:"expires_at"`
+	Reason         string       `json:"reason,omitempty"`
+	Status         string       `json:"status"`
+	RequestedByID  *uint        `json:"requested_by_id"`
+	DecidedByID    *uint        `json:"decided_by_id"`
+	DecidedAt      *time.Time   `json:"decided_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// NewGrantResponse monta a visão da concessão
+func NewGrantResponse(grant RoleGrant) GrantResponse {
+	response := GrantResponse{
+		ID:             grant.ID,
+		UserID:         grant.UserID,
+		RoleID:         grant.RoleID,
+		OrganizationID: grant.OrganizationID,
+		StartsAt:       grant.StartsAt,
+		ExpiresAt:      grant.ExpiresAt,
+		Reason:         grant.Reason,
+		Status:         grant.Status,
+		RequestedByID:  grant.RequestedByID,
+		DecidedByID:    grant.DecidedByID,
+		DecidedAt:      grant.DecidedAt,
+		CreatedAt:      grant.CreatedAt,
+	}
+	if grant.Role.ID != 0 {
+		response.Role = &RoleSummary{ID: grant.Role.ID, Name: grant.Role.Name}
+	}
+	return response
+}
+
+// NewGrantResponses monta a visão de uma lista de concessões
+func NewGrantResponses(grants []RoleGrant) []GrantResponse {
+	responses := make([]GrantResponse, len(grants))
+	for i, grant := range grants {
+		responses[i] = NewGrantResponse(grant)
+	}
+	return responses
+}
+
+// UserStatusRequest altera a situação da conta; o motivo é obrigatório para suspender ou banir
+type UserStatusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// AssignRoleRequest atribui uma role a um usuário
+type AssignRoleRequest struct {
+	RoleID uint `json:"role_id"`
+}
+
+// ValidationErrorResponse lista os erros de validação por campo
+type ValidationErrorResponse struct {
+	Message string              `json:"message"`
+	Errors  map[string][]string `json:"errors"`
+}
+
+// PaginatedResponse representa uma resposta paginada, por página (current_page) ou por cursor
+// (next_cursor/prev_cursor). O total é omitido quando a contagem é dispensada com count=false.
 type PaginatedResponse struct {
 	Status      int         `json:"status"`
 	Message     string      `json:"message"`
 	Data        interface{} `json:"data"`
-	TotalCount  int         `json:"total_count"`
-	TotalPages  int         `json:"total_pages"`
-	CurrentPage int         `json:"current_page"`
+	TotalCount  *int        `json:"total_count,omitempty"`
+	TotalPages  *int        `json:"total_pages,omitempty"`
+	CurrentPage int         `json:"current_page,omitempty"`
 	Limit       int         `json:"limit"`
+	NextCursor  string      `json:"next_cursor,omitempty"`
+	PrevCursor  string      `json:"prev_cursor,omitempty"`
 }
 
 // ErrorResponse representa uma resposta de erro
@@ -64,11 +562,26 @@ type ErrorResponse struct {
 
 // Constantes para capacidades
 const (
-	CapabilityCreateUser  = "create:user"
-	CapabilityReadUser    = "read:user"
-	CapabilityUpdateUser  = "update:user"
-	CapabilityDeleteUser  = "delete:user"
-	CapabilityManageRoles = "manage:roles"
-	CapabilityViewTasks   = "view:tasks"
-	CapabilityManageTasks = "manage:tasks"
+	CapabilityCreateUser     = "create:user"
+	CapabilityReadUser       = "read:user"
+	CapabilityUpdateUser     = "update:user"
+	CapabilityDeleteUser     = "delete:user"
+	CapabilityPurgeUser      = "purge:user"
+	CapabilityManageStatus   = "manage:user-status"
+	CapabilityManageRoles    = "manage:roles"
+	CapabilityManageSessions = "manage:sessions"
+	CapabilityManageLockouts = "manage:lockouts"
+	CapabilityViewTasks      = "view:tasks"
+	CapabilityManageTasks    = "manage:tasks"
+	CapabilityManageOrgs     = "manage:organizations"
+	CapabilityManageGroups   = "manage:groups"
+	CapabilityManageGrants   = "manage:grants"
+	CapabilityCheckAuthz     = "check:authz"
+)
+
+// Capacidades com escopo de dono (ver policy.Can): ":own" vale para os próprios recursos e ":any" para todos
+const (
+	CapabilityReadOwnUser   = CapabilityReadUser + ":own"
+	CapabilityUpdateOwnUser = CapabilityUpdateUser + ":own"
+	CapabilityViewOwnTasks  = CapabilityViewTasks + ":own"
 )
@@ -0,0 +1,66 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/utils"
+)
+
+// Escopos das capacidades sobre recursos com dono: "verbo:recurso:own" vale apenas
+// para os recursos do próprio usuário e "verbo:recurso:any" para qualquer recurso
+const (
+	ScopeOwn = "own"
+	ScopeAny = "any"
+)
+
+// Decision é o resultado da verificação de acesso a um recurso
+type Decision struct {
+	Allowed    bool   `json:"allowed"`
+	Capability string `json:"capability"`       // Capacidade que concedeu (ou que faltou para) o acesso
+	Scope      string `json:"scope,omitempty"`  // Escopo que concedeu o acesso (own ou any)
+	Reason     string `json:"reason,omitempty"` // Motivo da negação (ou padrão que concedeu, ver DecideCapability)
+}
+
+// Can decide se o usuário das claims pode exercer a capacidade ("verbo:recurso") sobre o recurso do dono informado.
+// Qualquer recurso exige "verbo:recurso:any"; o próprio recurso aceita também "verbo:recurso:own".
+// Como padrões mais curtos cobrem os mais específicos, "verbo:recurso" concede os dois escopos.
+func Can(claims *utils.Claims, capability string, ownerID uint) Decision {
+	role, err := utils.ResolveClaims(claims)
+	if err != nil {
+		return Decision{Capability: capability, Reason: "role não encontrada"}
+	}
+	return Decide(role.Capabilities, claims.UserID, capability, ownerID)
+}
+
+// DecideCapability decide uma capacidade que não depende de um recurso com dono, indicando na decisão
+// o padrão concedido que a permitiu ou o motivo da negação
+func DecideCapability(granted []string, capability string) Decision {
+	allowed, pattern := rbac.Explain(granted, capability)
+	switch {
+	case allowed:
+		return Decision{Allowed: true, Capability: capability, Reason: fmt.Sprintf("concedida por %s", pattern)}
+	case pattern != "":
+		return Decision{Capability: capability, Reason: fmt.Sprintf("negada por %s", pattern)}
+	default:
+		return Decision{Capability: capability, Reason: fmt.Sprintf("capacidade %s ausente", capability)}
+	}
+}
+
+// Decide é como Can, mas a partir das capacidades já resolvidas do usuário
+func Decide(granted []string, userID uint, capability string, ownerID uint) Decision {
+	anyScope := capability + ":" + ScopeAny
+	if rbac.Allowed(granted, anyScope) {
+		return Decision{Allowed: true, Capability: anyScope, Scope: ScopeAny}
+	}
+
+	ownScope := capability + ":" + ScopeOwn
+	if ownerID != 0 && ownerID == userID {
+		if rbac.Allowed(granted, ownScope) {
+			return Decision{Allowed: true, Capability: ownScope, Scope: ScopeOwn}
+		}
+		return Decision{Capability: ownScope, Reason: fmt.Sprintf("capacidade %s ausente", ownScope)}
+	}
+
+	return Decision{Capability: anyScope, Reason: fmt.Sprintf("o recurso pertence a outro usuário e a capacidade %s está ausente", anyScope)}
+}
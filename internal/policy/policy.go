@@ -0,0 +1,131 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// AnyMethod faz a regra valer para qualquer método HTTP
+const AnyMethod = "*"
+
+// Rule associa um método e um template de rota do mux às exigências de acesso
+type Rule struct {
+	Method       string   // Método HTTP ou AnyMethod
+	Path         string   // Template da rota no mux, ex.: "/admin/users/{id:[0-9]+}"
+	Public       bool     // Dispensa autenticação
+	Capabilities []string // Todas as capacidades são exigidas
+	Roles        []string // Basta possuir uma das roles
+	GlobalOnly   bool     // Recusada em sessões abertas em uma organização
+}
+
+// Policy é o conjunto de regras de acesso das rotas
+type Policy struct {
+	rules map[string]Rule
+}
+
+// New cria a política, rejeitando regras incompletas ou duplicadas
+func New(rules ...Rule) (*Policy, error) {
+	p := &Policy{rules: make(map[string]Rule, len(rules))}
+	for _, rule := range rules {
+		if rule.Method == "" || rule.Path == "" {
+			return nil, fmt.Errorf("regra sem método ou rota: %+v", rule)
+		}
+		if rule.Public && (len(rule.Capabilities) > 0 || len(rule.Roles) > 0 || rule.GlobalOnly) {
+			return nil, fmt.Errorf("regra pública com exigências de acesso: %s %s", rule.Method, rule.Path)
+		}
+
+		rule.Method = strings.ToUpper(rule.Method)
+		key := ruleKey(rule.Method, rule.Path)
+		if _, exists := p.rules[key]; exists {
+			return nil, fmt.Errorf("regra duplicada: %s %s", rule.Method, rule.Path)
+		}
+		p.rules[key] = rule
+	}
+	return p, nil
+}
+
+// MustNew é como New, mas entra em pânico se as regras forem inválidas
+func MustNew(rules ...Rule) *Policy {
+	p, err := New(rules...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Match retorna a regra do método e do template de rota; regras do método têm precedência sobre AnyMethod
+func (p *Policy) Match(method, path string) (Rule, bool) {
+	if rule, ok := p.rules[ruleKey(strings.ToUpper(method), path)]; ok {
+		return rule, true
+	}
+	rule, ok := p.rules[ruleKey(AnyMethod, path)]
+	return rule, ok
+}
+
+// Rules retorna as regras ordenadas por rota e método
+func (p *Policy) Rules() []Rule {
+	rules := make([]Rule, 0, len(p.rules))
+	for _, rule := range p.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Path != rules[j].Path {
+			return rules[i].Path < rules[j].Path
+		}
+		return rules[i].Method < rules[j].Method
+	})
+	return rules
+}
+
+// Verify confere a política com as rotas registradas no mux: toda rota precisa de uma regra
+// e toda regra precisa corresponder a uma rota, para que nenhuma das listas fique desatualizada
+func (p *Policy) Verify(router *mux.Router) error {
+	used := make(map[string]bool, len(p.rules))
+	var problems []string
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		// Rotas sem handler são apenas prefixos de subrouters
+		if route.GetHandler() == nil {
+			return nil
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{AnyMethod}
+		}
+		for _, method := range methods {
+			rule, ok := p.Match(method, path)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("rota sem regra de acesso: %s %s", method, path))
+				continue
+			}
+			used[ruleKey(rule.Method, rule.Path)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range p.Rules() {
+		if !used[ruleKey(rule.Method, rule.Path)] {
+			problems = append(problems, fmt.Sprintf("regra sem rota correspondente: %s %s", rule.Method, rule.Path))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("política de acesso inconsistente com as rotas:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+func ruleKey(method, path string) string {
+	return method + " " + path
+}
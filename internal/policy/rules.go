@@ -0,0 +1,111 @@
+package policy
+
+import "github.com/jeffemart/Gotham/internal/models"
+
+// Default é a política de acesso das rotas registradas em routes.SetupRoutes.
+// Toda rota nova precisa de uma regra aqui; main verifica a correspondência na inicialização.
+var Default = MustNew(
+	// Documentação
+	Rule{Method: AnyMethod, Path: "/swagger/", Public: true},
+
+	// Cadastro e consulta de usuários
+	Rule{Method: "GET", Path: "/users", Capabilities: []string{models.CapabilityReadUser + ":" + ScopeAny}},
+	Rule{Method: "GET", Path: "/users/{id:[0-9]+}"}, // Dono ou read:user:any, verificado no handler
+	Rule{Method: "POST", Path: "/users", Public: true},
+	Rule{Method: "PUT", Path: "/users/{id:[0-9]+}"}, // Dono ou update:user:any, verificado no handler
+
+	// Autenticação
+	Rule{Method: "POST", Path: "/login", Public: true},
+	Rule{Method: "POST", Path: "/login/mfa", Public: true},
+	Rule{Method: "POST", Path: "/login/mfa/enroll", Public: true},
+	Rule{Method: "POST", Path: "/password/forgot", Public: true},
+	Rule{Method: "POST", Path: "/password/reset", Public: true},
+	Rule{Method: "POST", Path: "/email/verify", Public: true},
+	Rule{Method: "POST", Path: "/email/verify/resend", Public: true},
+	Rule{Method: "POST", Path: "/refresh_token", Public: true},
+	Rule{Method: "GET", Path: "/.well-known/jwks.json", Public: true},
+	Rule{Method: "POST", Path: "/logout"},
+	Rule{Method: "POST", Path: "/logout/all"},
+
+	// Decisões de autorização (outro sujeito que não o próprio usuário exige check:authz, verificado no handler)
+	Rule{Method: "POST", Path: "/authz/check"},
+	Rule{Method: "POST", Path: "/authz/check/batch"},
+
+	// Administração de usuários
+	Rule{Method: "PUT", Path: "/admin/users/{id:[0-9]+}", Capabilities: []string{models.CapabilityUpdateUser}},
+	Rule{Method: "DELETE", Path: "/admin/users/{id:[0-9]+}", Capabilities: []string{models.CapabilityDeleteUser}},
+	Rule{Method: "POST", Path: "/admin/users/{id:[0-9]+}/revoke-sessions", Capabilities: []string{models.CapabilityManageSessions}},
+	Rule{Method: "GET", Path: "/admin/users/{id:[0-9]+}/sessions", Capabilities: []string{models.CapabilityManageSessions}},
+	Rule{Method: "DELETE", Path: "/admin/users/{id:[0-9]+}/sessions/{sid}", Capabilities: []string{models.CapabilityManageSessions}},
+	Rule{Method: "GET", Path: "/admin/users/{id:[0-9]+}/lockout", Capabilities: []string{models.CapabilityManageLockouts}},
+	Rule{Method: "DELETE", Path: "/admin/users/{id:[0-9]+}/lockout", Capabilities: []string{models.CapabilityManageLockouts}},
+	Rule{Method: "GET", Path: "/admin/lockouts/ip/{ip}", Capabilities: []string{models.CapabilityManageLockouts}, GlobalOnly: true},
+	Rule{Method: "DELETE", Path: "/admin/lockouts/ip/{ip}", Capabilities: []string{models.CapabilityManageLockouts}, GlobalOnly: true},
+	Rule{Method: "PUT", Path: "/admin/users/{id:[0-9]+}/role", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "PUT", Path: "/admin/users/{id:[0-9]+}/status", Capabilities: []string{models.CapabilityManageStatus}},
+
+	// Lixeira de usuários (apenas no escopo global, verificado no handler)
+	Rule{Method: "GET", Path: "/admin/users/deleted", Capabilities: []string{models.CapabilityDeleteUser}},
+	Rule{Method: "DELETE", Path: "/admin/users/deleted/{id:[0-9]+}", Capabilities: []string{models.CapabilityPurgeUser}},
+	Rule{Method: "POST", Path: "/admin/users/{id:[0-9]+}/restore", Capabilities: []string{models.CapabilityDeleteUser}},
+
+	// Roles e permissões
+	Rule{Method: "GET", Path: "/admin/roles", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "POST", Path: "/admin/roles", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "GET", Path: "/admin/roles/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "PUT", Path: "/admin/roles/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "DELETE", Path: "/admin/roles/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "GET", Path: "/admin/roles/{id:[0-9]+}/capabilities", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "PUT", Path: "/admin/roles/{id:[0-9]+}/capabilities", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "PUT", Path: "/admin/roles/{id:[0-9]+}/permissions", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "GET", Path: "/admin/roles/cache", Capabilities: []string{models.CapabilityManageRoles}, GlobalOnly: true},
+	Rule{Method: "GET", Path: "/admin/permissions", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "POST", Path: "/admin/permissions", Capabilities: []string{models.CapabilityManageRoles}},
+	Rule{Method: "DELETE", Path: "/admin/permissions/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageRoles}},
+
+	// Organizações e membros (em uma organização, apenas a própria; ver handlers.findOrganization)
+	Rule{Method: "GET", Path: "/admin/organizations", Capabilities: []string{models.CapabilityManageOrgs}},
+	Rule{Method: "POST", Path: "/admin/organizations", Capabilities: []string{models.CapabilityManageOrgs}},
+	Rule{Method: "GET", Path: "/admin/organizations/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageOrgs}},
+	Rule{Method: "PUT", Path: "/admin/organizations/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageOrgs}},
+	Rule{Method: "DELETE", Path: "/admin/organizations/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageOrgs}},
+	Rule{Method: "GET", Path: "/admin/organizations/{id:[0-9]+}/members", Capabilities: []string{models.CapabilityManageOrgs}},
+	Rule{Method: "PUT", Path: "/admin/organizations/{id:[0-9]+}/members/{user_id:[0-9]+}", Capabilities: []string{models.CapabilityManageOrgs}},
+	Rule{Method: "DELETE", Path: "/admin/organizations/{id:[0-9]+}/members/{user_id:[0-9]+}", Capabilities: []string{models.CapabilityManageOrgs}},
+
+	// Grupos e membros (em uma organização, apenas os grupos dela; ver handlers.findGroup).
+	// Atribuir roles a um grupo equivale a atribuí-las aos membros, por isso também exige manage:roles.
+	Rule{Method: "GET", Path: "/admin/groups", Capabilities: []string{models.CapabilityManageGroups}},
+	Rule{Method: "POST", Path: "/admin/groups", Capabilities: []string{models.CapabilityManageGroups, models.CapabilityManageRoles}},
+	Rule{Method: "GET", Path: "/admin/groups/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageGroups}},
+	Rule{Method: "PUT", Path: "/admin/groups/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageGroups, models.CapabilityManageRoles}},
+	Rule{Method: "DELETE", Path: "/admin/groups/{id:[0-9]+}", Capabilities: []string{models.CapabilityManageGroups}},
+	Rule{Method: "GET", Path: "/admin/groups/{id:[0-9]+}/members", Capabilities: []string{models.CapabilityManageGroups}},
+	Rule{Method: "PUT", Path: "/admin/groups/{id:[0-9]+}/members/{user_id:[0-9]+}", Capabilities: []string{models.CapabilityManageGroups, models.CapabilityManageRoles}},
+	Rule{Method: "DELETE", Path: "/admin/groups/{id:[0-9]+}/members/{user_id:[0-9]+}", Capabilities: []string{models.CapabilityManageGroups, models.CapabilityManageRoles}},
+
+	// Concessões temporárias de roles (em uma organização, apenas as dela; ver handlers.findGrant).
+	// Aprovar equivale a atribuir a role, por isso também exige manage:roles.
+	Rule{Method: "GET", Path: "/admin/grants", Capabilities: []string{models.CapabilityManageGrants}},
+	Rule{Method: "POST", Path: "/admin/grants", Capabilities: []string{models.CapabilityManageGrants}},
+	Rule{Method: "POST", Path: "/admin/grants/{id:[0-9]+}/approve", Capabilities: []string{models.CapabilityManageGrants, models.CapabilityManageRoles}},
+	Rule{Method: "POST", Path: "/admin/grants/{id:[0-9]+}/reject", Capabilities: []string{models.CapabilityManageGrants}},
+	Rule{Method: "POST", Path: "/admin/grants/{id:[0-9]+}/revoke", Capabilities: []string{models.CapabilityManageGrants}},
+
+	// Usuário autenticado
+	Rule{Method: "GET", Path: "/me"},
+	Rule{Method: "PATCH", Path: "/me"},
+	Rule{Method: "POST", Path: "/me/password"},
+	Rule{Method: "GET", Path: "/me/sessions"},
+	Rule{Method: "DELETE", Path: "/me/sessions/{id}"},
+	Rule{Method: "GET", Path: "/me/organizations"},
+	Rule{Method: "GET", Path: "/me/grants"},
+	Rule{Method: "POST", Path: "/me/grants"},
+	Rule{Method: "POST", Path: "/me/mfa/enroll"},
+	Rule{Method: "POST", Path: "/me/mfa/confirm"},
+	Rule{Method: "POST", Path: "/me/mfa/disable"},
+	Rule{Method: "POST", Path: "/me/mfa/recovery-codes"},
+
+	// Tarefas
+	Rule{Method: "GET", Path: "/protected/tasks"}, // view:tasks:own ou view:tasks:any, verificado no handler
+)
@@ -0,0 +1,180 @@
+package rbac
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultCache guarda as roles resolvidas por Resolve. Pode ser substituído na inicialização
+// (ver NewCache); nil desativa o cache.
+var DefaultCache = NewCache(1024, 5*time.Minute)
+
+// Cache é um cache LRU, com expiração, das roles resolvidas, indexado por chave (ver roleKey e userKey).
+// As roles retornadas são compartilhadas entre as requisições e não devem ser alteradas.
+type Cache struct {
+	mu            sync.Mutex
+	capacity      int
+	ttl           time.Duration
+	entries       map[string]*list.Element
+	order         *list.List // Mais recente na frente
+	generation    uint64     // Incrementada a cada invalidação
+	hits          uint64
+	misses        uint64
+	evictions     uint64
+	invalidations uint64
+	now           func() time.Time
+}
+
+// roleKey é a chave de uma role resolvida no cache
+func roleKey(roleID uint) string {
+	return fmt.Sprintf("role:%d", roleID)
+}
+
+// userKey é a chave das capacidades efetivas de um usuário (role direta e grupos) no cache
+func userKey(userID, organizationID, roleID uint) string {
+	return fmt.Sprintf("user:%d:%d:%d", userID, organizationID, roleID)
+}
+
+// CacheStats são os contadores do cache de roles
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+	Size          int    `json:"size"`
+	Capacity      int    `json:"capacity"`
+	TTLSeconds    int64  `json:"ttl_seconds"`
+}
+
+type cacheEntry struct {
+	key       string
+	role      *ResolvedRole
+	expiresAt time.Time
+}
+
+// NewCache cria um cache com a capacidade (número de roles) e o tempo de vida informados
+func NewCache(capacity int, ttl time.Duration) *Cache {
+	return &Cache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// SetClock substitui o relógio usado para a expiração (usado nos testes)
+func (c *Cache) SetClock(now func() time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Get retorna a role resolvida, se estiver no cache e não tiver expirado
+func (c *Cache) Get(key string) (*ResolvedRole, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		c.misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits++
+	return entry.role, true
+}
+
+// Generation retorna a geração atual do cache; ela muda a cada invalidação
+func (c *Cache) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// Set armazena a role resolvida, descartando a menos usada se o cache estiver cheio
+func (c *Cache) Set(key string, role *ResolvedRole) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, role, time.Time{})
+}
+
+// SetIfGeneration armazena a role apenas se o cache não foi invalidado desde a geração informada.
+// Evita guardar uma role lida do banco antes de uma alteração cuja invalidação já ocorreu.
+func (c *Cache) SetIfGeneration(generation uint64, key string, role *ResolvedRole) {
+	c.SetIfGenerationUntil(generation, key, role, time.Time{})
+}
+
+// SetIfGenerationUntil é como SetIfGeneration, mas a entrada expira no instante informado se ele vier antes
+// do tempo de vida do cache. Usado quando o resultado muda em um momento conhecido (ver ResolveUser).
+func (c *Cache) SetIfGenerationUntil(generation uint64, key string, role *ResolvedRole, until time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	c.set(key, role, until)
+}
+
+// Purge remove todas as roles do cache
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.generation++
+	c.invalidations++
+}
+
+// Stats retorna os contadores do cache
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Hits:          c.hits,
+		Misses:        c.misses,
+		Evictions:     c.evictions,
+		Invalidations: c.invalidations,
+		Size:          c.order.Len(),
+		Capacity:      c.capacity,
+		TTLSeconds:    int64(c.ttl / time.Second),
+	}
+}
+
+func (c *Cache) set(key string, role *ResolvedRole, until time.Time) {
+	if c.capacity <= 0 || c.ttl <= 0 {
+		return
+	}
+
+	expiresAt := c.now().Add(c.ttl)
+	if !until.IsZero() && until.Before(expiresAt) {
+		expiresAt = until
+	}
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		entry.role = role
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, role: role, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+func (c *Cache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,55 @@
+package rbac
+
+import (
+	"context"
+	"log"
+
+	"github.com/jeffemart/Gotham/internal/database"
+)
+
+// InvalidationChannel é o canal do Redis em que as instâncias avisam sobre alterações nas roles
+const InvalidationChannel = "rbac:invalidate"
+
+// Invalidate limpa o cache local de roles e avisa as demais instâncias pelo Redis.
+// Deve ser chamada depois que uma alteração em roles ou permissões for confirmada no banco.
+// Como as capacidades são herdadas, qualquer alteração invalida o cache inteiro.
+func Invalidate() {
+	if DefaultCache != nil {
+		DefaultCache.Purge()
+	}
+	if database.RedisClient == nil {
+		return
+	}
+	if err := database.RedisClient.Publish(database.Ctx, InvalidationChannel, "*").Err(); err != nil {
+		log.Printf("Erro ao publicar invalidação do cache de roles: %v", err)
+	}
+}
+
+// SubscribeInvalidations escuta as invalidações publicadas por qualquer instância e limpa o cache local
+// até que o contexto seja cancelado. Retorna depois que a inscrição no canal for confirmada.
+func SubscribeInvalidations(ctx context.Context) error {
+	pubsub := database.RedisClient.Subscribe(ctx, InvalidationChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return err
+	}
+
+	go func() {
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-messages:
+				if !ok {
+					return
+				}
+				if DefaultCache != nil {
+					DefaultCache.Purge()
+				}
+			}
+		}
+	}()
+	return nil
+}
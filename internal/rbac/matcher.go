@@ -0,0 +1,75 @@
+package rbac
+
+import "strings"
+
+// Capacidades seguem a convenção "verbo:recurso[:escopo]". Nos padrões concedidos às roles:
+//   - "*" em um segmento corresponde a qualquer valor naquele segmento ("read:*", "*:user");
+//   - um padrão mais curto cobre as capacidades mais específicas ("update:user" cobre "update:user:own");
+//   - o prefixo "!" nega a capacidade e prevalece sobre qualquer concessão ("!delete:user"); uma negação mais
+//     específica também bloqueia a exigência mais ampla que a inclui ("!delete:user:any" bloqueia "delete:user").
+const (
+	capabilitySeparator = ":"
+	denyPrefix          = "!"
+	wildcardSegment     = "*"
+)
+
+// MatchCapability verifica se o padrão cobre a capacidade exigida
+func MatchCapability(pattern, capability string) bool {
+	if pattern == "" || capability == "" {
+		return false
+	}
+
+	patternSegments := strings.Split(pattern, capabilitySeparator)
+	capabilitySegments := strings.Split(capability, capabilitySeparator)
+	if len(patternSegments) > len(capabilitySegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		if segment == "" || capabilitySegments[i] == "" {
+			return false
+		}
+		if segment != wildcardSegment && segment != capabilitySegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Allowed verifica se as capacidades concedidas permitem a capacidade exigida: ela precisa ser coberta por uma
+// concessão, e nenhuma negação pode alcançá-la, nem por inteiro nem em parte
+func Allowed(granted []string, capability string) bool {
+	allowed, _ := Explain(granted, capability)
+	return allowed
+}
+
+// Explain é como Allowed, mas retorna também o padrão que decidiu: a negação que prevaleceu ou a
+// primeira concessão que cobre a capacidade. Sem nenhum padrão correspondente, retorna false e "".
+func Explain(granted []string, capability string) (bool, string) {
+	match := ""
+	for _, pattern := range granted {
+		if denied, ok := strings.CutPrefix(pattern, denyPrefix); ok {
+			// Quem exige "delete:user" aceita qualquer escopo, inclusive o negado em "!delete:user:any"
+			if MatchCapability(denied, capability) || MatchCapability(capability, denied) {
+				return false, pattern
+			}
+			continue
+		}
+		if match == "" && MatchCapability(pattern, capability) {
+			match = pattern
+		}
+	}
+	return match != "", match
+}
+
+// Covers indica se as capacidades concedidas cobrem por inteiro a entrada informada, para decidir se quem as
+// possui pode concedê-la: o padrão precisa ser permitido, o que já exclui os alcançados em parte por uma negação
+// (quem tem "*" e "!delete:user" não cobre "*"). Negações sempre são cobertas, pois apenas restringem.
+func Covers(granted []string, pattern string) bool {
+	return IsDenial(pattern) || Allowed(granted, pattern)
+}
+
+// IsDenial indica se a entrada é uma negação ("!verbo:recurso")
+func IsDenial(pattern string) bool {
+	return strings.HasPrefix(pattern, denyPrefix)
+}
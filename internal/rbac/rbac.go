@@ -0,0 +1,329 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"gorm.io/gorm"
+)
+
+// CapabilityAll concede todas as capacidades
+const CapabilityAll = "*"
+
+var (
+	// ErrRoleNotFound indica que a role (ou uma role da hierarquia) não existe
+	ErrRoleNotFound = errors.New("role não encontrada")
+	// ErrRoleCycle indica que a hierarquia de roles forma um ciclo
+	ErrRoleCycle = errors.New("a hierarquia de roles forma um ciclo")
+)
+
+// ResolvedRole é a role com as capacidades e permissões herdadas de toda a hierarquia
+type ResolvedRole struct {
+	ID           uint     `json:"id"`
+	Name         string   `json:"name"`
+	Chain        []string `json:"chain"` // Nomes das roles, da própria role até a raiz
+	Capabilities []string `json:"capabilities"`
+	Permissions  []string `json:"permissions"`
+	Groups       []string `json:"groups,omitempty"` // Grupos que contribuíram com roles (ver ResolveUser)
+	Grants       []uint   `json:"grants,omitempty"` // Concessões temporárias em vigor (ver ResolveUser)
+}
+
+// Resolve carrega a role e acumula as capacidades e permissões dos seus ancestrais.
+// O resultado fica no DefaultCache e é compartilhado: quem chama não deve alterá-lo.
+func Resolve(roleID uint) (*ResolvedRole, error) {
+	cache := DefaultCache
+	if cache == nil {
+		return ResolveWith(database.DB, roleID)
+	}
+
+	key := roleKey(roleID)
+	if role, ok := cache.Get(key); ok {
+		return role, nil
+	}
+	generation := cache.Generation()
+	role, err := ResolveWith(database.DB, roleID)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetIfGeneration(generation, key, role)
+	return role, nil
+}
+
+// ResolveUser resolve as capacidades efetivas do usuário: a união da role direta (roleID) com as roles
+// dos grupos de que ele é membro e das concessões temporárias em vigor no escopo informado
+// (organizationID 0 para o escopo global). Uma negação ("!") em qualquer uma delas continua prevalecendo.
+// O resultado também fica no DefaultCache, no máximo até a próxima concessão começar ou expirar.
+func ResolveUser(userID, organizationID, roleID uint) (*ResolvedRole, error) {
+	cache := DefaultCache
+	if cache == nil {
+		return ResolveUserWith(database.DB, userID, organizationID, roleID)
+	}
+
+	key := userKey(userID, organizationID, roleID)
+	if role, ok := cache.Get(key); ok {
+		return role, nil
+	}
+	generation := cache.Generation()
+	role, until, err := resolveUser(database.DB, userID, organizationID, roleID, Resolve)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetIfGenerationUntil(generation, key, role, until)
+	return role, nil
+}
+
+// ResolveUserWith é como ResolveUser, mas usa a conexão (ou transação) informada e não passa pelo cache
+func ResolveUserWith(db *gorm.DB, userID, organizationID, roleID uint) (*ResolvedRole, error) {
+	role, _, err := resolveUser(db, userID, organizationID, roleID, func(id uint) (*ResolvedRole, error) {
+		return ResolveWith(db, id)
+	})
+	return role, err
+}
+
+// ResolveWith é como Resolve, mas usa a conexão (ou transação) informada e não passa pelo cache
+func ResolveWith(db *gorm.DB, roleID uint) (*ResolvedRole, error) {
+	return resolve(roleID, func(id uint) (*models.Role, error) {
+		var role models.Role
+		if err := db.Preload("Permissions").First(&role, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrRoleNotFound
+			}
+			return nil, fmt.Errorf("erro ao buscar role: %v", err)
+		}
+		return &role, nil
+	})
+}
+
+// ResolveAll resolve todas as roles com uma única consulta, indexando o resultado pelo ID
+func ResolveAll(db *gorm.DB) (map[uint]*ResolvedRole, error) {
+	var roles []models.Role
+	if err := db.Preload("Permissions").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar roles: %v", err)
+	}
+
+	byID := make(map[uint]*models.Role, len(roles))
+	for i := range roles {
+		byID[roles[i].ID] = &roles[i]
+	}
+	lookup := func(id uint) (*models.Role, error) {
+		role, ok := byID[id]
+		if !ok {
+			return nil, ErrRoleNotFound
+		}
+		return role, nil
+	}
+
+	resolved := make(map[uint]*ResolvedRole, len(roles))
+	for _, role := range roles {
+		r, err := resolve(role.ID, lookup)
+		if err != nil {
+			return nil, err
+		}
+		resolved[role.ID] = r
+	}
+	return resolved, nil
+}
+
+// ValidateParent verifica se a role pode herdar da role pai informada sem formar um ciclo
+func ValidateParent(db *gorm.DB, roleID, parentID uint) error {
+	if parentID == roleID {
+		return ErrRoleCycle
+	}
+
+	visited := map[uint]bool{roleID: true}
+	for id := &parentID; id != nil; {
+		if visited[*id] {
+			return ErrRoleCycle
+		}
+		visited[*id] = true
+
+		var parent models.Role
+		if err := db.Select("id", "parent_id").First(&parent, *id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoleNotFound
+			}
+			return fmt.Errorf("erro ao buscar role: %v", err)
+		}
+		id = parent.ParentID
+	}
+	return nil
+}
+
+// HasCapabilities verifica se as capacidades concedidas permitem todas as capacidades exigidas
+func HasCapabilities(granted []string, required ...string) bool {
+	for _, capability := range required {
+		if !Allowed(granted, capability) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAdmin indica se a role resolvida concede acesso total, sem nenhuma negação
+func (r *ResolvedRole) IsAdmin() bool {
+	admin := false
+	for _, capability := range r.Capabilities {
+		if IsDenial(capability) {
+			return false
+		}
+		if capability == CapabilityAll {
+			admin = true
+		}
+	}
+	return admin
+}
+
+// groupGrant é uma role recebida por meio de um grupo
+type groupGrant struct {
+	GroupName string
+	RoleID    uint
+}
+
+// resolveUser soma à role direta as roles dos grupos e das concessões em vigor do usuário no escopo,
+// resolvidas por resolveRole. Retorna também o instante da próxima concessão a começar ou expirar
+// (zero se não houver), a partir do qual o resultado deixa de valer.
+func resolveUser(db *gorm.DB, userID, organizationID, roleID uint, resolveRole func(uint) (*ResolvedRole, error)) (*ResolvedRole, time.Time, error) {
+	var until time.Time
+	direct, err := resolveRole(roleID)
+	if err != nil {
+		return nil, until, err
+	}
+
+	query := db.Table("group_users").
+		Select("groups.name AS group_name, group_roles.role_id").
+		Joins("JOIN groups ON groups.id = group_users.group_id AND groups.deleted_at IS NULL").
+		Joins("JOIN group_roles ON group_roles.group_id = groups.id").
+		Where("group_users.user_id = ?", userID).
+		Order("groups.id, group_roles.role_id")
+	if organizationID == 0 {
+		query = query.Where("groups.organization_id IS NULL")
+	} else {
+		query = query.Where("groups.organization_id = ?", organizationID)
+	}
+	var groupGrants []groupGrant
+	if err := query.Scan(&groupGrants).Error; err != nil {
+		return nil, until, fmt.Errorf("erro ao buscar roles dos grupos: %v", err)
+	}
+
+	// Concessões aprovadas que ainda não expiraram; as futuras só definem quando o resultado muda
+	now := time.Now()
+	grantsQuery := db.Where("user_id = ? AND status = ? AND expires_at > ?", userID, models.GrantApproved, now).Order("id")
+	if organizationID == 0 {
+		grantsQuery = grantsQuery.Where("organization_id IS NULL")
+	} else {
+		grantsQuery = grantsQuery.Where("organization_id = ?", organizationID)
+	}
+	var roleGrants []models.RoleGrant
+	if err := grantsQuery.Find(&roleGrants).Error; err != nil {
+		return nil, until, fmt.Errorf("erro ao buscar concessões de roles: %v", err)
+	}
+
+	var active []models.RoleGrant
+	for _, grant := range roleGrants {
+		transition := grant.ExpiresAt
+		if grant.StartsAt.After(now) {
+			transition = grant.StartsAt
+		} else {
+			active = append(active, grant)
+		}
+		if until.IsZero() || transition.Before(until) {
+			until = transition
+		}
+	}
+	if len(groupGrants) == 0 && len(active) == 0 {
+		return direct, until, nil
+	}
+
+	// O resultado de resolveRole pode ser compartilhado pelo cache; a união é feita em uma cópia
+	effective := &ResolvedRole{ID: direct.ID, Name: direct.Name, Capabilities: []string{}, Permissions: []string{}}
+	union(effective, direct)
+	seenGroups := map[string]bool{}
+	for _, grant := range groupGrants {
+		role, err := resolveRole(grant.RoleID)
+		if err != nil {
+			return nil, until, err
+		}
+		union(effective, role)
+		if !seenGroups[grant.GroupName] {
+			seenGroups[grant.GroupName] = true
+			effective.Groups = append(effective.Groups, grant.GroupName)
+		}
+	}
+	for _, grant := range active {
+		role, err := resolveRole(grant.RoleID)
+		if err != nil {
+			return nil, until, err
+		}
+		union(effective, role)
+		effective.Grants = append(effective.Grants, grant.ID)
+	}
+	return effective, until, nil
+}
+
+// union acrescenta ao destino as roles, capacidades e permissões da role resolvida, sem repetições
+func union(dst, src *ResolvedRole) {
+	dst.Chain = appendMissing(dst.Chain, src.Chain)
+	dst.Capabilities = appendMissing(dst.Capabilities, src.Capabilities)
+	dst.Permissions = appendMissing(dst.Permissions, src.Permissions)
+}
+
+// appendMissing acrescenta os valores que ainda não estão na lista
+func appendMissing(list, values []string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// resolve percorre a hierarquia a partir da role, acumulando capacidades e permissões sem repetições
+func resolve(roleID uint, lookup func(uint) (*models.Role, error)) (*ResolvedRole, error) {
+	resolved := &ResolvedRole{Capabilities: []string{}, Permissions: []string{}}
+	seenCapabilities := map[string]bool{}
+	seenPermissions := map[string]bool{}
+	visited := map[uint]bool{}
+
+	for id := &roleID; id != nil; {
+		if visited[*id] {
+			return nil, ErrRoleCycle
+		}
+		visited[*id] = true
+
+		role, err := lookup(*id)
+		if err != nil {
+			return nil, err
+		}
+		if resolved.ID == 0 {
+			resolved.ID = role.ID
+			resolved.Name = role.Name
+		}
+		resolved.Chain = append(resolved.Chain, role.Name)
+
+		for _, capability := range role.Capabilities {
+			if !seenCapabilities[capability] {
+				seenCapabilities[capability] = true
+				resolved.Capabilities = append(resolved.Capabilities, capability)
+			}
+		}
+		for _, permission := range role.Permissions {
+			if !seenPermissions[permission.Name] {
+				seenPermissions[permission.Name] = true
+				resolved.Permissions = append(resolved.Permissions, permission.Name)
+			}
+		}
+
+		id = role.ParentID
+	}
+	return resolved, nil
+}
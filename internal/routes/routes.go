@@ -4,10 +4,9 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/jeffemart/Gotham/internal/handlers"
 	"github.com/jeffemart/Gotham/internal/middlewares"
+	"github.com/jeffemart/Gotham/internal/policy"
 	"github.com/rs/cors"
 	httpSwagger "github.com/swaggo/http-swagger"
-	"net/http"
-	"github.com/jeffemart/Gotham/internal/models"
 )
 
 // SetupRoutes configura todas as rotas da aplicação
@@ -30,13 +29,17 @@ func SetupRoutes(r *mux.Router) {
 	})
 	r.Use(c.Handler)
 
+	// Autenticação e autorização de todas as rotas conforme a política declarada em policy.Default
+	r.Use(middlewares.AuthorizationMiddleware(policy.Default))
+
 	// Endpoint da documentação Swagger
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
-	// Rotas públicas
+	// Consulta de usuários (restrita à organização da sessão)
 	// @Summary Lista todos os usuários
-	// @Description Retorna todos os usuários registrados no sistema
+	// @Description Retorna os usuários registrados no sistema; em uma organização, apenas os seus membros
 	// @Tags users
+	// @Security Bearer
 	// @Produce json
 	// @Success 200 {array} map[string]interface{}
 	// @Router /users [get]
@@ -45,53 +48,223 @@ func SetupRoutes(r *mux.Router) {
 	// @Summary Retorna um usuário pelo ID
 	// @Description Busca um usuário pelo ID fornecido
 	// @Tags users
+	// @Security Bearer
 	// @Param id path int true "ID do usuário"
 	// @Produce json
 	// @Success 200 {object} map[string]interface{}
 	// @Router /users/{id} [get]
 	r.HandleFunc("/users/{id:[0-9]+}", handlers.GetUser).Methods("GET")
 
+	// Rotas públicas
+
 	// @Summary Cria um novo usuário
 	// @Description Adiciona um usuário ao sistema
 	// @Tags users
 	// @Accept json
 	// @Produce json
-	// @Param user body map[string]interface{} true "Dados do usuário"
-	// @Success 201 {object} map[string]interface{}
+	// @Param user body models.CreateUserRequest true "Dados do usuário"
+	// @Success 201 {object} models.UserResponse
 	// @Router /users [post]
 	r.HandleFunc("/users", handlers.CreateUser).Methods("POST")
 
+	// Atualização do próprio perfil ou de um usuário (documentada em handlers.UpdateUser)
+	r.HandleFunc("/users/{id:[0-9]+}", handlers.UpdateUser).Methods("PUT")
+
 	// @Summary Realiza login
 	// @Description Autentica um usuário e retorna o token JWT
 	// @Tags auth
 	// @Accept json
 	// @Produce json
 	// @Param credentials body map[string]interface{} true "Credenciais de login"
-	// @Success 200 {object} map[string]string
+	// @Success 200 {object} models.TokenResponse
 	// @Router /login [post]
 	r.HandleFunc("/login", handlers.Login).Methods("POST")
 
-	// Rotas protegidas com capacidades específicas
+	// @Summary Conclui o login com MFA
+	// @Description Valida o código TOTP ou de recuperação do desafio retornado por /login
+	// @Tags auth
+	// @Accept json
+	// @Produce json
+	// @Param request body models.MFALoginRequest true "Desafio e código"
+	// @Success 200 {object} models.MFALoginResponse
+	// @Router /login/mfa [post]
+	r.HandleFunc("/login/mfa", handlers.LoginMFA).Methods("POST")
+
+	// @Summary Inicia o cadastro do MFA no login
+	// @Description Para roles que exigem MFA, gera o segredo TOTP a partir do desafio de login
+	// @Tags auth
+	// @Accept json
+	// @Produce json
+	// @Param request body models.MFAEnrollRequest true "Desafio"
+	// @Success 200 {object} models.MFAEnrollmentResponse
+	// @Router /login/mfa/enroll [post]
+	r.HandleFunc("/login/mfa/enroll", handlers.LoginMFAEnroll).Methods("POST")
+
+	// @Summary Solicita a redefinição de senha
+	// @Description Envia por e-mail um link de redefinição de senha de uso único
+	// @Tags auth
+	// @Accept json
+	// @Produce json
+	// @Param request body models.ForgotPasswordRequest true "E-mail"
+	// @Success 200 {object} map[string]string
+	// @Router /password/forgot [post]
+	r.HandleFunc("/password/forgot", handlers.ForgotPassword).Methods("POST")
+
+	// @Summary Redefine a senha
+	// @Description Redefine a senha com o token recebido por e-mail
+	// @Tags auth
+	// @Accept json
+	// @Produce json
+	// @Param request body models.ResetPasswordRequest true "Token e nova senha"
+	// @Success 200 {object} map[string]string
+	// @Router /password/reset [post]
+	r.HandleFunc("/password/reset", handlers.ResetPassword).Methods("POST")
+
+	// @Summary Confirma o e-mail
+	// @Description Confirma o e-mail com o token do link de verificação
+	// @Tags auth
+	// @Accept json
+	// @Produce json
+	// @Param request body models.VerifyEmailRequest true "Token"
+	// @Success 200 {object} map[string]string
+	// @Router /email/verify [post]
+	r.HandleFunc("/email/verify", handlers.VerifyEmail).Methods("POST")
+
+	// @Summary Reenvia o link de verificação
+	// @Description Reenvia o link de verificação para contas não verificadas
+	// @Tags auth
+	// @Accept json
+	// @Produce json
+	// @Param request body models.ResendVerificationRequest true "E-mail"
+	// @Success 200 {object} map[string]string
+	// @Router /email/verify/resend [post]
+	r.HandleFunc("/email/verify/resend", handlers.ResendVerification).Methods("POST")
+
+	// @Summary Renova os tokens
+	// @Description Troca um refresh token válido por um novo par de tokens (rotação)
+	// @Tags auth
+	// @Accept json
+	// @Produce json
+	// @Param refresh body models.RefreshRequest true "Refresh token"
+	// @Success 200 {object} models.TokenResponse
+	// @Router /refresh_token [post]
+	r.HandleFunc("/refresh_token", handlers.RefreshToken).Methods("POST")
+
+	// @Summary Chaves públicas de assinatura
+	// @Description Publica as chaves públicas (JWKS) usadas para verificar os tokens
+	// @Tags auth
+	// @Produce json
+	// @Success 200 {object} keyring.JWKS
+	// @Router /.well-known/jwks.json [get]
+	r.HandleFunc("/.well-known/jwks.json", handlers.JWKS).Methods("GET")
+
+	// @Summary Encerra a sessão atual
+	// @Description Revoga o token atual e a sessão a que ele pertence
+	// @Tags auth
+	// @Security Bearer
+	// @Produce json
+	// @Success 200 {object} map[string]string
+	// @Router /logout [post]
+	r.HandleFunc("/logout", handlers.Logout).Methods("POST")
+
+	// @Summary Encerra todas as sessões
+	// @Description Revoga todas as sessões do usuário autenticado
+	// @Tags auth
+	// @Security Bearer
+	// @Produce json
+	// @Success 200 {object} map[string]string
+	// @Router /logout/all [post]
+	r.HandleFunc("/logout/all", handlers.LogoutAll).Methods("POST")
+
+	// Decisões de autorização para outros serviços
+	r.HandleFunc("/authz/check", handlers.CheckAuthorization).Methods("POST")
+	r.HandleFunc("/authz/check/batch", handlers.CheckAuthorizationBatch).Methods("POST")
+
+	// Rotas administrativas; as capacidades exigidas estão em policy.Default
 	adminRoutes := r.PathPrefix("/admin").Subrouter()
-	adminRoutes.Use(middlewares.AuthMiddleware)
-	
-	// Rotas de usuário com capacidades específicas
-	adminRoutes.Handle("/users/{id:[0-9]+}", 
-		middlewares.CapabilityMiddleware(models.CapabilityUpdateUser)(
-			http.HandlerFunc(handlers.UpdateUser),
-		)).Methods("PUT")
-	
-	adminRoutes.Handle("/users/{id:[0-9]+}", 
-		middlewares.CapabilityMiddleware(models.CapabilityDeleteUser)(
-			http.HandlerFunc(handlers.DeleteUser),
-		)).Methods("DELETE")
+
+	// Usuários
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}", handlers.UpdateUser).Methods("PUT")
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}", handlers.DeleteUser).Methods("DELETE")
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}/role", handlers.AssignUserRole).Methods("PUT")
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}/status", handlers.SetUserStatus).Methods("PUT")
+
+	// Lixeira de usuários
+	adminRoutes.HandleFunc("/users/deleted", handlers.GetDeletedUsers).Methods("GET")
+	adminRoutes.HandleFunc("/users/deleted/{id:[0-9]+}", handlers.PurgeUser).Methods("DELETE")
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}/restore", handlers.RestoreUser).Methods("POST")
+
+	// Sessões
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}/revoke-sessions", handlers.RevokeUserSessions).Methods("POST")
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}/sessions", handlers.GetUserSessions).Methods("GET")
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}/sessions/{sid}", handlers.DeleteUserSession).Methods("DELETE")
+
+	// Bloqueios de login por excesso de falhas
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}/lockout", handlers.GetUserLockout).Methods("GET")
+	adminRoutes.HandleFunc("/users/{id:[0-9]+}/lockout", handlers.ClearUserLockout).Methods("DELETE")
+	adminRoutes.HandleFunc("/lockouts/ip/{ip}", handlers.GetIPLockout).Methods("GET")
+	adminRoutes.HandleFunc("/lockouts/ip/{ip}", handlers.ClearIPLockout).Methods("DELETE")
+
+	// Roles e permissões
+	adminRoutes.HandleFunc("/roles", handlers.GetRoles).Methods("GET")
+	adminRoutes.HandleFunc("/roles", handlers.CreateRole).Methods("POST")
+	adminRoutes.HandleFunc("/roles/{id:[0-9]+}", handlers.GetRole).Methods("GET")
+	adminRoutes.HandleFunc("/roles/{id:[0-9]+}", handlers.UpdateRole).Methods("PUT")
+	adminRoutes.HandleFunc("/roles/{id:[0-9]+}", handlers.DeleteRole).Methods("DELETE")
+	adminRoutes.HandleFunc("/roles/{id:[0-9]+}/capabilities", handlers.GetRoleCapabilities).Methods("GET")
+	adminRoutes.HandleFunc("/roles/{id:[0-9]+}/capabilities", handlers.UpdateRoleCapabilities).Methods("PUT")
+	adminRoutes.HandleFunc("/roles/{id:[0-9]+}/permissions", handlers.UpdateRolePermissions).Methods("PUT")
+	adminRoutes.HandleFunc("/roles/cache", handlers.GetRoleCacheStats).Methods("GET")
+	adminRoutes.HandleFunc("/permissions", handlers.GetPermissions).Methods("GET")
+	adminRoutes.HandleFunc("/permissions", handlers.CreatePermission).Methods("POST")
+	adminRoutes.HandleFunc("/permissions/{id:[0-9]+}", handlers.DeletePermission).Methods("DELETE")
+
+	// Organizações e membros
+	adminRoutes.HandleFunc("/organizations", handlers.GetOrganizations).Methods("GET")
+	adminRoutes.HandleFunc("/organizations", handlers.CreateOrganization).Methods("POST")
+	adminRoutes.HandleFunc("/organizations/{id:[0-9]+}", handlers.GetOrganization).Methods("GET")
+	adminRoutes.HandleFunc("/organizations/{id:[0-9]+}", handlers.UpdateOrganization).Methods("PUT")
+	adminRoutes.HandleFunc("/organizations/{id:[0-9]+}", handlers.DeleteOrganization).Methods("DELETE")
+	adminRoutes.HandleFunc("/organizations/{id:[0-9]+}/members", handlers.GetOrganizationMembers).Methods("GET")
+	adminRoutes.HandleFunc("/organizations/{id:[0-9]+}/members/{user_id:[0-9]+}", handlers.SetOrganizationMember).Methods("PUT")
+	adminRoutes.HandleFunc("/organizations/{id:[0-9]+}/members/{user_id:[0-9]+}", handlers.RemoveOrganizationMember).Methods("DELETE")
+
+	// Grupos e membros
+	adminRoutes.HandleFunc("/groups", handlers.GetGroups).Methods("GET")
+	adminRoutes.HandleFunc("/groups", handlers.CreateGroup).Methods("POST")
+	adminRoutes.HandleFunc("/groups/{id:[0-9]+}", handlers.GetGroup).Methods("GET")
+	adminRoutes.HandleFunc("/groups/{id:[0-9]+}", handlers.UpdateGroup).Methods("PUT")
+	adminRoutes.HandleFunc("/groups/{id:[0-9]+}", handlers.DeleteGroup).Methods("DELETE")
+	adminRoutes.HandleFunc("/groups/{id:[0-9]+}/members", handlers.GetGroupMembers).Methods("GET")
+	adminRoutes.HandleFunc("/groups/{id:[0-9]+}/members/{user_id:[0-9]+}", handlers.AddGroupMember).Methods("PUT")
+	adminRoutes.HandleFunc("/groups/{id:[0-9]+}/members/{user_id:[0-9]+}", handlers.RemoveGroupMember).Methods("DELETE")
+
+	// Concessões temporárias de roles
+	adminRoutes.HandleFunc("/grants", handlers.GetGrants).Methods("GET")
+	adminRoutes.HandleFunc("/grants", handlers.CreateGrant).Methods("POST")
+	adminRoutes.HandleFunc("/grants/{id:[0-9]+}/approve", handlers.ApproveGrant).Methods("POST")
+	adminRoutes.HandleFunc("/grants/{id:[0-9]+}/reject", handlers.RejectGrant).Methods("POST")
+	adminRoutes.HandleFunc("/grants/{id:[0-9]+}/revoke", handlers.RevokeGrant).Methods("POST")
+
+	// Rotas do usuário autenticado
+	meRoutes := r.PathPrefix("/me").Subrouter()
+
+	meRoutes.HandleFunc("", handlers.GetMe).Methods("GET")
+	meRoutes.HandleFunc("", handlers.UpdateMe).Methods("PATCH")
+	meRoutes.HandleFunc("/password", handlers.ChangeMyPassword).Methods("POST")
+	meRoutes.HandleFunc("/sessions", handlers.GetMySessions).Methods("GET")
+	meRoutes.HandleFunc("/sessions/{id}", handlers.DeleteMySession).Methods("DELETE")
+	meRoutes.HandleFunc("/organizations", handlers.GetMyOrganizations).Methods("GET")
+	meRoutes.HandleFunc("/grants", handlers.GetMyGrants).Methods("GET")
+	meRoutes.HandleFunc("/grants", handlers.RequestMyGrant).Methods("POST")
+
+	meRoutes.HandleFunc("/mfa/enroll", handlers.EnrollMFA).Methods("POST")
+	meRoutes.HandleFunc("/mfa/confirm", handlers.ConfirmMFA).Methods("POST")
+	meRoutes.HandleFunc("/mfa/disable", handlers.DisableMFA).Methods("POST")
+	meRoutes.HandleFunc("/mfa/recovery-codes", handlers.RegenerateRecoveryCodes).Methods("POST")
 
 	// Rotas de tarefas
 	protectedRoutes := r.PathPrefix("/protected").Subrouter()
-	protectedRoutes.Use(middlewares.AuthMiddleware)
-	
-	protectedRoutes.Handle("/tasks", 
-		middlewares.CapabilityMiddleware(models.CapabilityViewTasks)(
-			http.HandlerFunc(handlers.GetTasks),
-		)).Methods("GET")
+
+	protectedRoutes.HandleFunc("/tasks", handlers.GetTasks).Methods("GET")
 }
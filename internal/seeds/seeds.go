@@ -1,6 +1,8 @@
 package seeds
 
 import (
+	"time"
+
 	"github.com/jeffemart/Gotham/internal/database"
 	"github.com/jeffemart/Gotham/internal/models"
 	"golang.org/x/crypto/bcrypt"
@@ -26,6 +28,7 @@ func SeedDatabase() error {
 		Capabilities: []string{
 			"*", // Admin tem todas as capacidades
 		},
+		RequireMFA: true, // Administradores precisam de MFA no login
 	}
 	database.DB.Create(&adminRole)
 
@@ -41,13 +44,26 @@ func SeedDatabase() error {
 	}
 	database.DB.Create(&agentRole)
 
+	// Usuários comuns só leem e editam o próprio perfil e veem as próprias tarefas
+	userRole := models.Role{
+		Name: "user",
+		Capabilities: []string{
+			models.CapabilityReadOwnUser,
+			models.CapabilityUpdateOwnUser,
+			models.CapabilityViewOwnTasks,
+		},
+	}
+	database.DB.Create(&userRole)
+
 	// Criar usuário admin
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
+	verifiedAt := time.Now()
 	adminUser := models.User{
-		Name:     "Admin",
-		Email:    "admin@example.com",
-		Password: string(hashedPassword),
-		RoleID:   adminRole.ID,
+		Name:       "Admin",
+		Email:      "admin@example.com",
+		Password:   string(hashedPassword),
+		RoleID:     adminRole.ID,
+		VerifiedAt: &verifiedAt,
 	}
 	database.DB.Create(&adminUser)
 
@@ -4,17 +4,20 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 )
 
 // Config estrutura que contém as configurações do projeto
 type Config struct {
 	App struct {
-		Name  string
-		Env   string
-		Key   string
-		Port  string
-		Debug bool
-		URL   string
+		Name       string
+		Env        string
+		Key        string
+		Port       string
+		Debug      bool
+		URL        string
+		TrustProxy bool
 	}
 	Database struct {
 		Driver   string
@@ -30,6 +33,54 @@ type Config struct {
 		Password string
 		DB       int
 	}
+	Mail struct {
+		Driver      string
+		Host        string
+		Port        string
+		Username    string
+		Password    string
+		Encryption  string
+		FromAddress string
+		FromName    string
+		FileDir     string
+		Timeout     time.Duration // Limite de uma sessão SMTP, da conexão ao fim do envio
+	}
+	Auth struct {
+		PasswordResetTTL         time.Duration
+		EmailVerificationTTL     time.Duration
+		RequireEmailVerification bool
+	}
+	Password struct {
+		MinLength     int
+		RequireUpper  bool
+		RequireLower  bool
+		RequireDigit  bool
+		RequireSymbol bool
+		BannedWords   []string
+	}
+	Lockout struct {
+		AccountThreshold int
+		IPThreshold      int
+		Window           time.Duration
+		BaseDelay        time.Duration
+		MaxDelay         time.Duration
+	}
+	RBAC struct {
+		CacheSize        int
+		CacheTTL         time.Duration
+		GrantMaxDuration time.Duration
+		DefaultRole      string
+	}
+	Users struct {
+		DeletedRetention time.Duration
+		PurgeInterval    time.Duration
+	}
+	JWT struct {
+		Algorithm       string
+		KeysDir         string
+		AccessTokenTTL  time.Duration
+		RefreshTokenTTL time.Duration
+	}
 }
 
 // LoadSettings carrega as configurações das variáveis de ambiente
@@ -43,6 +94,7 @@ func LoadSettings() *Config {
 	config.App.Port = getEnv("APP_PORT", "8000")
 	config.App.Debug = getEnvAsBool("APP_DEBUG", true)
 	config.App.URL = getEnv("APP_URL", "http://localhost")
+	config.App.TrustProxy = getEnvAsBool("APP_TRUST_PROXY", false)
 
 	// Configurações do banco de dados
 	config.Database.Driver = getEnv("DB_DRIVER", "postgres")
@@ -58,6 +110,58 @@ func LoadSettings() *Config {
 	config.Redis.Password = getEnv("REDIS_PASSWORD", "password")
 	config.Redis.DB = getEnvAsInt("REDIS_DB", 0)
 
+	// Configurações de e-mail (valores "null" são tratados como vazios)
+	config.Mail.Driver = getEnv("MAIL_DRIVER", "log")
+	config.Mail.Host = getEnvNullable("MAIL_HOST", "localhost")
+	config.Mail.Port = getEnvNullable("MAIL_PORT", "25")
+	config.Mail.Username = getEnvNullable("MAIL_USERNAME", "")
+	config.Mail.Password = getEnvNullable("MAIL_PASSWORD", "")
+	config.Mail.Encryption = getEnvNullable("MAIL_ENCRYPTION", "")
+	config.Mail.FromAddress = getEnv("MAIL_FROM_ADDRESS", "no-reply@gotham.local")
+	config.Mail.FromName = getEnv("MAIL_FROM_NAME", config.App.Name)
+	config.Mail.FileDir = getEnv("MAIL_FILE_DIR", "./storage/mail")
+	config.Mail.Timeout = getEnvAsDuration("MAIL_TIMEOUT", 30*time.Second)
+
+	// Configurações dos fluxos de autenticação
+	config.Auth.PasswordResetTTL = getEnvAsDuration("PASSWORD_RESET_TTL", time.Hour)
+	config.Auth.EmailVerificationTTL = getEnvAsDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour)
+	config.Auth.RequireEmailVerification = getEnvAsBool("AUTH_REQUIRE_EMAIL_VERIFICATION", false)
+
+	// Política de senhas
+	config.Password.MinLength = getEnvAsInt("PASSWORD_MIN_LENGTH", 8)
+	config.Password.RequireUpper = getEnvAsBool("PASSWORD_REQUIRE_UPPER", true)
+	config.Password.RequireLower = getEnvAsBool("PASSWORD_REQUIRE_LOWER", true)
+	config.Password.RequireDigit = getEnvAsBool("PASSWORD_REQUIRE_DIGIT", true)
+	config.Password.RequireSymbol = getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false)
+	config.Password.BannedWords = getEnvAsList("PASSWORD_BANNED_WORDS", []string{"password", "senha", "123456", "qwerty", "abc123", "letmein"})
+
+	// Configurações de proteção contra força bruta no login
+	config.Lockout.AccountThreshold = getEnvAsInt("LOGIN_LOCKOUT_ACCOUNT_THRESHOLD", 5)
+	config.Lockout.IPThreshold = getEnvAsInt("LOGIN_LOCKOUT_IP_THRESHOLD", 20)
+	config.Lockout.Window = getEnvAsDuration("LOGIN_LOCKOUT_WINDOW", 15*time.Minute)
+	config.Lockout.BaseDelay = getEnvAsDuration("LOGIN_LOCKOUT_BASE_DELAY", 30*time.Second)
+	config.Lockout.MaxDelay = getEnvAsDuration("LOGIN_LOCKOUT_MAX_DELAY", time.Hour)
+
+	// Cache das roles resolvidas (RBAC_CACHE_SIZE=0 desativa o cache)
+	config.RBAC.CacheSize = getEnvAsInt("RBAC_CACHE_SIZE", 1024)
+	config.RBAC.CacheTTL = getEnvAsDuration("RBAC_CACHE_TTL", 5*time.Minute)
+
+	// Duração máxima das concessões temporárias de roles
+	config.RBAC.GrantMaxDuration = getEnvAsDuration("RBAC_GRANT_MAX_DURATION", 24*time.Hour)
+
+	// Role global atribuída aos usuários no cadastro público
+	config.RBAC.DefaultRole = getEnv("RBAC_DEFAULT_ROLE", "user")
+
+	// Usuários excluídos ficam na lixeira pelo período de retenção; PurgeInterval 0 desativa a remoção automática
+	config.Users.DeletedRetention = getEnvAsDuration("USERS_DELETED_RETENTION", 30*24*time.Hour)
+	config.Users.PurgeInterval = getEnvAsDuration("USERS_PURGE_INTERVAL", time.Hour)
+
+	// Configurações dos tokens JWT
+	config.JWT.Algorithm = getEnv("JWT_ALGORITHM", "RS256")
+	config.JWT.KeysDir = getEnv("JWT_KEYS_DIR", "./keys")
+	config.JWT.AccessTokenTTL = getEnvAsDuration("JWT_ACCESS_TTL", 15*time.Minute)
+	config.JWT.RefreshTokenTTL = getEnvAsDuration("JWT_REFRESH_TTL", 30*24*time.Hour)
+
 	return config
 }
 
@@ -69,6 +173,15 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// Função auxiliar para obter variáveis de ambiente que aceitam "null" como valor vazio
+func getEnvNullable(key, defaultValue string) string {
+	value := getEnv(key, defaultValue)
+	if value == "null" {
+		return ""
+	}
+	return value
+}
+
 // Função auxiliar para obter variáveis de ambiente como inteiro
 func getEnvAsInt(key string, defaultValue int) int {
 	valueStr := getEnv(key, "")
@@ -100,3 +213,34 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return value
 }
+
+// Função auxiliar para obter variáveis de ambiente como lista separada por vírgulas
+func getEnvAsList(key string, defaultValue []string) []string {
+	valueStr, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	var values []string
+	for _, value := range strings.Split(valueStr, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Função auxiliar para obter variáveis de ambiente como duração (ex.: "15m", "720h")
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		log.Printf("Erro ao converter %s para duração: %v. Usando valor padrão: %s", key, err, defaultValue)
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/settings"
+)
+
+// emailVerificationAudience identifica os tokens de verificação de e-mail
+const emailVerificationAudience = "email_verification"
+
+// ErrEmailVerificationTokenInvalid indica um token de verificação inválido, expirado ou de um e-mail alterado
+var ErrEmailVerificationTokenInvalid = errors.New("token de verificação de e-mail inválido ou expirado")
+
+// ActionClaims são as claims dos tokens de ação enviados por e-mail; a finalidade é definida pela audience
+type ActionClaims struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	jwt.StandardClaims
+}
+
+// GenerateEmailVerificationToken gera o token assinado do link de verificação de e-mail
+func GenerateEmailVerificationToken(user models.User) (string, error) {
+	ttl := settings.LoadSettings().Auth.EmailVerificationTTL
+
+	claims := ActionClaims{
+		UserID: user.ID,
+		Email:  user.Email,
+		StandardClaims: jwt.StandardClaims{
+			Audience:  emailVerificationAudience,
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(ttl).Unix(),
+		},
+	}
+	return SignClaims(claims)
+}
+
+// ParseEmailVerificationToken valida a assinatura, a expiração e a finalidade do token de verificação
+func ParseEmailVerificationToken(tokenString string) (*ActionClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, verificationKey)
+	if err != nil {
+		return nil, ErrEmailVerificationTokenInvalid
+	}
+
+	claims, ok := token.Claims.(*ActionClaims)
+	if !ok || !token.Valid || !claims.VerifyAudience(emailVerificationAudience, true) {
+		return nil, ErrEmailVerificationTokenInvalid
+	}
+	return claims, nil
+}
+
+// EmailMatches indica se o token foi emitido para o e-mail atual do usuário
+func (c *ActionClaims) EmailMatches(email string) bool {
+	return strings.EqualFold(c.Email, email)
+}
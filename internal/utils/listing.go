@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayout é o formato aceito para datas sem horário nos filtros de listagem
+const dateLayout = "2006-01-02"
+
+// SortField é uma coluna de ordenação já validada
+type SortField struct {
+	Name   string // Nome exposto na API
+	Column string // Coluna usada na consulta
+	Desc   bool
+}
+
+// ParseSort interpreta uma ordenação no formato "-created_at,name": os campos são separados por vírgula e o
+// prefixo "-" indica ordem decrescente. Apenas os campos presentes em columns são aceitos; o valor do mapa é a
+// coluna usada na consulta, para que o nome exposto na API não precise coincidir com o do banco.
+func ParseSort(raw string, columns map[string]string) ([]SortField, error) {
+	var fields []SortField
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+		column, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("campo de ordenação inválido: %s", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("campo de ordenação repetido: %s", name)
+		}
+		seen[name] = true
+		fields = append(fields, SortField{Name: name, Column: column, Desc: desc})
+	}
+	return fields, nil
+}
+
+// WithTiebreaker acrescenta a coluna de desempate (crescente) quando ela ainda não faz parte da ordenação,
+// para que a ordem seja total e a paginação estável entre registros com os mesmos valores
+func WithTiebreaker(fields []SortField, name, column string) []SortField {
+	for _, field := range fields {
+		if field.Column == column {
+			return fields
+		}
+	}
+	return append(append([]SortField{}, fields...), SortField{Name: name, Column: column})
+}
+
+// ReverseSort inverte a direção de todos os campos, para percorrer a listagem para trás
+func ReverseSort(fields []SortField) []SortField {
+	reversed := make([]SortField, len(fields))
+	for i, field := range fields {
+		reversed[i] = SortField{Name: field.Name, Column: field.Column, Desc: !field.Desc}
+	}
+	return reversed
+}
+
+// SortString devolve a ordenação no formato aceito por ParseSort
+func SortString(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Name
+		if field.Desc {
+			parts[i] = "-" + field.Name
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// OrderClause monta a cláusula ORDER BY
+func OrderClause(fields []SortField) string {
+	clauses := make([]string, len(fields))
+	for i, field := range fields {
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		clauses[i] = field.Column + " " + direction
+	}
+	return strings.Join(clauses, ", ")
+}
+
+// Cursor é a posição de uma listagem paginada por chave (keyset): os valores dos campos de ordenação do
+// último registro entregue (ou do primeiro, ao voltar). A ordenação viaja junto para que o cursor não seja
+// reaproveitado em outra ordem.
+type Cursor struct {
+	Sort     string   `json:"s"`
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// ErrInvalidCursor indica um cursor malformado ou de outra ordenação
+var ErrInvalidCursor = errors.New("cursor inválido")
+
+// EncodeCursor serializa o cursor em um texto opaco, seguro para a query string
+func EncodeCursor(cursor Cursor) string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor interpreta um cursor gerado por EncodeCursor
+func DecodeCursor(raw string) (Cursor, error) {
+	var cursor Cursor
+	payload, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil || json.Unmarshal(payload, &cursor) != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// KeysetCondition monta a condição que seleciona os registros posteriores à posição values na ordem fields,
+// expandida campo a campo para aceitar direções diferentes: (a > ?) OR (a = ? AND b < ?) OR ...
+// Os campos não podem ser nulos e a ordem precisa ser total (ver WithTiebreaker).
+func KeysetCondition(fields []SortField, values []interface{}) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	for i, field := range fields {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fields[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+		operator := " > ?"
+		if field.Desc {
+			operator = " < ?"
+		}
+		parts = append(parts, field.Column+operator)
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// ParseDateBound interpreta um limite de intervalo de datas em RFC 3339 ou AAAA-MM-DD. O fim do intervalo (end)
+// deve ser comparado como exclusivo; uma data sem horário no fim cobre o dia inteiro (vira o início do dia seguinte).
+func ParseDateBound(value string, end bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data inválida: %s", value)
+	}
+	if end {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return parsed, nil
+}
+
+// LikePattern monta o padrão de busca por trecho para LIKE/ILIKE, escapando os curingas digitados pelo usuário
+func LikePattern(term string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+	return "%" + escaped + "%"
+}
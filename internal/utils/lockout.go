@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/settings"
+)
+
+// Escopos dos contadores de falhas de login
+const (
+	LockoutScopeAccount = "account"
+	LockoutScopeIP      = "ip"
+)
+
+// Prefixos das chaves usadas no Redis para a proteção contra força bruta
+const (
+	loginFailuresPrefix = "login_failures:"
+	loginLockPrefix     = "login_lock:"
+)
+
+// CheckLoginLock retorna por quanto tempo o login ainda está bloqueado para a conta ou para o IP (zero se liberado)
+func CheckLoginLock(email, ip string) (time.Duration, error) {
+	var wait time.Duration
+	for _, key := range []string{lockKey(LockoutScopeAccount, email), lockKey(LockoutScopeIP, ip)} {
+		ttl, err := database.RedisClient.PTTL(database.Ctx, key).Result()
+		if err != nil {
+			return 0, fmt.Errorf("erro ao verificar bloqueio de login: %v", err)
+		}
+		if ttl > wait {
+			wait = ttl
+		}
+	}
+	return wait, nil
+}
+
+// RegisterLoginFailure contabiliza uma falha de login para a conta e para o IP.
+// Ao atingir o limite, o login é bloqueado por um tempo que dobra a cada nova falha.
+func RegisterLoginFailure(email, ip string) error {
+	config := settings.LoadSettings()
+
+	if err := registerFailure(LockoutScopeAccount, email, config.Lockout.AccountThreshold); err != nil {
+		return err
+	}
+	return registerFailure(LockoutScopeIP, ip, config.Lockout.IPThreshold)
+}
+
+// ResetLoginFailures zera os contadores da conta após um login bem-sucedido.
+// O contador do IP é mantido para que um atacante não o zere entrando na própria conta.
+func ResetLoginFailures(email string) error {
+	err := database.RedisClient.Del(database.Ctx,
+		failuresKey(LockoutScopeAccount, email),
+		lockKey(LockoutScopeAccount, email),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("erro ao zerar falhas de login: %v", err)
+	}
+	return nil
+}
+
+// GetLockout retorna o estado do bloqueio de uma conta (e-mail) ou de um IP
+func GetLockout(scope, target string) (*models.LockoutStatus, error) {
+	failures, err := database.RedisClient.Get(database.Ctx, failuresKey(scope, target)).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("erro ao buscar falhas de login: %v", err)
+	}
+
+	ttl, err := database.RedisClient.PTTL(database.Ctx, lockKey(scope, target)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar bloqueio de login: %v", err)
+	}
+
+	status := &models.LockoutStatus{Scope: scope, Target: target, Failures: failures}
+	if ttl > 0 {
+		status.Locked = true
+		status.RetryAfter = int64((ttl + time.Second - 1) / time.Second)
+	}
+	return status, nil
+}
+
+// ClearLockout remove o bloqueio e zera as falhas de uma conta (e-mail) ou de um IP
+func ClearLockout(scope, target string) error {
+	if err := database.RedisClient.Del(database.Ctx, failuresKey(scope, target), lockKey(scope, target)).Err(); err != nil {
+		return fmt.Errorf("erro ao remover bloqueio de login: %v", err)
+	}
+	return nil
+}
+
+// registerFailure incrementa o contador da janela e aplica o bloqueio progressivo ao atingir o limite
+func registerFailure(scope, target string, threshold int) error {
+	config := settings.LoadSettings()
+	key := failuresKey(scope, target)
+
+	failures, err := database.RedisClient.Incr(database.Ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("erro ao registrar falha de login: %v", err)
+	}
+	// A janela começa na primeira falha
+	if failures == 1 {
+		if err := database.RedisClient.Expire(database.Ctx, key, config.Lockout.Window).Err(); err != nil {
+			return fmt.Errorf("erro ao registrar falha de login: %v", err)
+		}
+	}
+
+	if threshold <= 0 || failures < int64(threshold) {
+		return nil
+	}
+
+	if err := database.RedisClient.Set(database.Ctx, lockKey(scope, target), failures, lockoutDelay(failures-int64(threshold))).Err(); err != nil {
+		return fmt.Errorf("erro ao bloquear login: %v", err)
+	}
+	return nil
+}
+
+// lockoutDelay calcula o bloqueio progressivo: BaseDelay * 2^excesso, limitado a MaxDelay
+func lockoutDelay(excess int64) time.Duration {
+	config := settings.LoadSettings()
+
+	delay := config.Lockout.BaseDelay
+	for i := int64(0); i < excess && delay < config.Lockout.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > config.Lockout.MaxDelay {
+		delay = config.Lockout.MaxDelay
+	}
+	return delay
+}
+
+func failuresKey(scope, target string) string {
+	return loginFailuresPrefix + scope + ":" + normalizeLockoutTarget(scope, target)
+}
+
+func lockKey(scope, target string) string {
+	return loginLockPrefix + scope + ":" + normalizeLockoutTarget(scope, target)
+}
+
+// normalizeLockoutTarget evita que variações de caixa no e-mail ou de notação do IP escapem do contador
+func normalizeLockoutTarget(scope, target string) string {
+	target = strings.TrimSpace(target)
+	if scope == LockoutScopeAccount {
+		return strings.ToLower(target)
+	}
+	if ip := net.ParseIP(target); ip != nil {
+		return ip.String()
+	}
+	return target
+}
@@ -0,0 +1,264 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/pkg/totp"
+)
+
+// Prefixos das chaves usadas no Redis para o MFA
+const (
+	mfaChallengePrefix = "mfa_challenge:"
+	mfaEnrollPrefix    = "mfa_enroll:"
+	mfaUsedStepPrefix  = "mfa_used_step:"
+)
+
+// Parâmetros do MFA
+const (
+	mfaChallengeTTL    = 5 * time.Minute
+	mfaEnrollTTL       = 10 * time.Minute
+	mfaMaxAttempts     = 5
+	mfaSkew            = 1
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+var (
+	// ErrMFAChallengeInvalid indica um desafio de MFA inexistente, expirado ou com tentativas esgotadas
+	ErrMFAChallengeInvalid = errors.New("desafio de MFA inválido ou expirado")
+	// ErrMFAEnrollmentNotFound indica que não há cadastro de MFA pendente para o usuário
+	ErrMFAEnrollmentNotFound = errors.New("nenhum cadastro de MFA pendente")
+	// ErrMFACodeInvalid indica um código TOTP ou de recuperação inválido
+	ErrMFACodeInvalid = errors.New("código de MFA inválido")
+)
+
+// MFARequired indica se o usuário precisa concluir o MFA para fazer login (Role deve estar carregada)
+func MFARequired(user models.User) bool {
+	return user.MFAEnabled || user.Role.RequireMFA
+}
+
+// CreateMFAChallenge registra o desafio da segunda etapa do login e retorna o token opaco que o identifica.
+// A organização escolhida no login é guardada no desafio para a emissão dos tokens.
+func CreateMFAChallenge(userID, organizationID uint) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	key := mfaChallengePrefix + hashToken(token)
+	pipe := database.RedisClient.TxPipeline()
+	pipe.HSet(database.Ctx, key, "user_id", userID, "organization_id", organizationID, "attempts", 0)
+	pipe.Expire(database.Ctx, key, mfaChallengeTTL)
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		return "", fmt.Errorf("erro ao registrar desafio de MFA: %v", err)
+	}
+	return token, nil
+}
+
+// challengeScript contabiliza uma tentativa no desafio e o descarta ao atingir o limite
+var challengeScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+if attempts > tonumber(ARGV[1]) then
+	redis.call("DEL", KEYS[1])
+	return -1
+end
+return tonumber(redis.call("HGET", KEYS[1], "user_id"))
+`)
+
+// MFAChallengeUser contabiliza uma tentativa no desafio e retorna o ID do usuário a que ele pertence
+func MFAChallengeUser(token string) (uint, error) {
+	keys := []string{mfaChallengePrefix + hashToken(token)}
+
+	userID, err := challengeScript.Run(database.Ctx, database.RedisClient, keys, mfaMaxAttempts).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar desafio de MFA: %v", err)
+	}
+	if userID <= 0 {
+		return 0, ErrMFAChallengeInvalid
+	}
+	return uint(userID), nil
+}
+
+// MFAChallengeOrganization retorna a organização escolhida no login que gerou o desafio
+func MFAChallengeOrganization(token string) (uint, error) {
+	value, err := database.RedisClient.HGet(database.Ctx, mfaChallengePrefix+hashToken(token), "organization_id").Uint64()
+	if err == redis.Nil {
+		return 0, ErrMFAChallengeInvalid
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar desafio de MFA: %v", err)
+	}
+	return uint(value), nil
+}
+
+// ConsumeMFAChallenge descarta o desafio após o login ser concluído
+func ConsumeMFAChallenge(token string) error {
+	if err := database.RedisClient.Del(database.Ctx, mfaChallengePrefix+hashToken(token)).Err(); err != nil {
+		return fmt.Errorf("erro ao descartar desafio de MFA: %v", err)
+	}
+	return nil
+}
+
+// StartMFAEnrollment gera um novo segredo TOTP pendente de confirmação para o usuário
+func StartMFAEnrollment(user models.User) (*models.MFAEnrollmentResponse, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.RedisClient.Set(database.Ctx, mfaEnrollKey(user.ID), secret, mfaEnrollTTL).Err(); err != nil {
+		return nil, fmt.Errorf("erro ao registrar cadastro de MFA: %v", err)
+	}
+
+	issuer := settings.LoadSettings().App.Name
+	return &models.MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(issuer, user.Email, secret),
+	}, nil
+}
+
+// CompleteMFAEnrollment confirma o cadastro com um código gerado pelo segredo pendente,
+// habilita o MFA do usuário e retorna os novos códigos de recuperação
+func CompleteMFAEnrollment(user *models.User, code string) ([]string, error) {
+	secret, err := database.RedisClient.Get(database.Ctx, mfaEnrollKey(user.ID)).Result()
+	if err == redis.Nil {
+		return nil, ErrMFAEnrollmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cadastro de MFA: %v", err)
+	}
+
+	if !verifyTOTP(user.ID, secret, code) {
+		return nil, ErrMFACodeInvalid
+	}
+
+	user.MFAEnabled = true
+	user.MFASecret = secret
+	if err := database.DB.Model(user).Select("MFAEnabled", "MFASecret").Updates(user).Error; err != nil {
+		return nil, fmt.Errorf("erro ao habilitar MFA: %v", err)
+	}
+	database.RedisClient.Del(database.Ctx, mfaEnrollKey(user.ID))
+
+	return GenerateRecoveryCodes(user.ID)
+}
+
+// VerifyMFA valida um código TOTP ou, se informado, um código de recuperação (que é consumido)
+func VerifyMFA(user models.User, code, recoveryCode string) error {
+	if !user.MFAEnabled {
+		return ErrMFACodeInvalid
+	}
+
+	if code != "" {
+		if verifyTOTP(user.ID, user.MFASecret, code) {
+			return nil
+		}
+		return ErrMFACodeInvalid
+	}
+
+	if recoveryCode != "" {
+		return consumeRecoveryCode(user.ID, recoveryCode)
+	}
+
+	return ErrMFACodeInvalid
+}
+
+// DisableMFA desabilita o MFA do usuário e remove os seus códigos de recuperação
+func DisableMFA(user *models.User) error {
+	user.MFAEnabled = false
+	user.MFASecret = ""
+	if err := database.DB.Model(user).Select("MFAEnabled", "MFASecret").Updates(user).Error; err != nil {
+		return fmt.Errorf("erro ao desabilitar MFA: %v", err)
+	}
+
+	if err := database.DB.Unscoped().Where("user_id = ?", user.ID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return fmt.Errorf("erro ao remover códigos de recuperação: %v", err)
+	}
+	return nil
+}
+
+// GenerateRecoveryCodes substitui os códigos de recuperação do usuário e retorna os novos códigos em texto puro
+func GenerateRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	records := make([]models.MFARecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		code, err := randomRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		records[i] = models.MFARecoveryCode{UserID: userID, CodeHash: hashToken(code)}
+	}
+
+	tx := database.DB.Begin()
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("erro ao remover códigos de recuperação: %v", err)
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("erro ao salvar códigos de recuperação: %v", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("erro ao salvar códigos de recuperação: %v", err)
+	}
+
+	return codes, nil
+}
+
+// consumeRecoveryCode marca o código de recuperação como utilizado; a atualização condicional garante o uso único
+func consumeRecoveryCode(userID uint, code string) error {
+	normalized := strings.ToLower(strings.TrimSpace(code))
+
+	result := database.DB.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(normalized)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("erro ao consumir código de recuperação: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrMFACodeInvalid
+	}
+	return nil
+}
+
+// verifyTOTP valida o código e impede que o mesmo intervalo seja usado duas vezes (replay)
+func verifyTOTP(userID uint, secret, code string) bool {
+	step, ok := totp.Validate(secret, code, time.Now(), mfaSkew)
+	if !ok {
+		return false
+	}
+
+	key := mfaUsedStepPrefix + strconv.FormatUint(uint64(userID), 10) + ":" + strconv.FormatInt(step, 10)
+	window := time.Duration(2*mfaSkew+1) * totp.Period * time.Second
+	fresh, err := database.RedisClient.SetNX(database.Ctx, key, 1, window).Result()
+	return err == nil && fresh
+}
+
+// randomRecoveryCode gera um código no formato xxxxx-xxxxx
+func randomRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("erro ao gerar código de recuperação: %v", err)
+	}
+
+	encoded := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf))[:recoveryCodeLength]
+	half := recoveryCodeLength / 2
+	return encoded[:half] + "-" + encoded[half:], nil
+}
+
+func mfaEnrollKey(userID uint) string {
+	return mfaEnrollPrefix + strconv.FormatUint(uint64(userID), 10)
+}
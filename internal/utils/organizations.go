@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrNotMember indica que o usuário não pertence à organização
+var ErrNotMember = errors.New("o usuário não pertence à organização")
+
+// FindMembership retorna o vínculo do usuário com a organização, com a role carregada
+func FindMembership(userID, organizationID uint) (*models.Membership, error) {
+	var membership models.Membership
+	err := database.DB.Preload("Role").
+		Where("user_id = ? AND organization_id = ?", userID, organizationID).
+		First(&membership).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotMember
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar vínculo com a organização: %v", err)
+	}
+	return &membership, nil
+}
+
+// TenantUsers restringe a consulta de usuários aos membros da organização.
+// Com organizationID 0 (escopo global) a consulta não é restringida.
+func TenantUsers(organizationID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if organizationID == 0 {
+			return db
+		}
+		return db.Where("users.id IN (SELECT user_id FROM memberships WHERE organization_id = ?)", organizationID)
+	}
+}
+
+// TenantRoles restringe a consulta de roles às roles globais e às da organização.
+// Com organizationID 0 (escopo global) a consulta não é restringida.
+func TenantRoles(organizationID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if organizationID == 0 {
+			return db
+		}
+		return db.Where("(roles.organization_id = ? OR roles.organization_id IS NULL)", organizationID)
+	}
+}
+
+// RoleUsableIn indica se a role pode ser atribuída no escopo informado: roles globais valem em qualquer
+// escopo e roles de uma organização só valem nela (organizationID 0 é o escopo global)
+func RoleUsableIn(role models.Role, organizationID uint) bool {
+	if role.OrganizationID == nil {
+		return true
+	}
+	return *role.OrganizationID == organizationID
+}
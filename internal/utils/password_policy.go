@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/pkg/validator"
+)
+
+// PasswordPolicy retorna a política de senhas configurada
+func PasswordPolicy() validator.PasswordPolicy {
+	config := settings.LoadSettings()
+	return validator.PasswordPolicy{
+		MinLength:     config.Password.MinLength,
+		RequireUpper:  config.Password.RequireUpper,
+		RequireLower:  config.Password.RequireLower,
+		RequireDigit:  config.Password.RequireDigit,
+		RequireSymbol: config.Password.RequireSymbol,
+		BannedWords:   config.Password.BannedWords,
+	}
+}
+
+// ValidatePassword valida a senha contra a política configurada e os dados do usuário
+func ValidatePassword(password, email, name string) []string {
+	return PasswordPolicy().Validate(password, email, name)
+}
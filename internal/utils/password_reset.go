@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/settings"
+)
+
+// ErrPasswordResetTokenInvalid indica um token de redefinição inexistente, expirado ou já utilizado
+var ErrPasswordResetTokenInvalid = errors.New("token de redefinição de senha inválido ou expirado")
+
+// CreatePasswordResetToken gera um token de redefinição de senha para o usuário.
+// Tokens anteriores ainda não utilizados são invalidados; apenas o hash do novo token é armazenado.
+func CreatePasswordResetToken(userID uint) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	tx := database.DB.Begin()
+	if err := tx.Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now).Error; err != nil {
+		tx.Rollback()
+		return "", fmt.Errorf("erro ao invalidar tokens anteriores: %v", err)
+	}
+
+	record := models.PasswordResetToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(settings.LoadSettings().Auth.PasswordResetTTL),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		tx.Rollback()
+		return "", fmt.Errorf("erro ao salvar token de redefinição: %v", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return "", fmt.Errorf("erro ao salvar token de redefinição: %v", err)
+	}
+
+	return token, nil
+}
+
+// PasswordResetTokenUser retorna o ID do usuário de um token válido sem consumi-lo
+func PasswordResetTokenUser(token string) (uint, error) {
+	var record models.PasswordResetToken
+	err := database.DB.
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hashToken(token), time.Now()).
+		First(&record).Error
+	if err != nil {
+		return 0, ErrPasswordResetTokenInvalid
+	}
+	return record.UserID, nil
+}
+
+// ConsumePasswordResetToken marca o token como utilizado e retorna o ID do usuário a que ele pertence
+func ConsumePasswordResetToken(token string) (uint, error) {
+	var record models.PasswordResetToken
+	if err := database.DB.Where("token_hash = ?", hashToken(token)).First(&record).Error; err != nil {
+		return 0, ErrPasswordResetTokenInvalid
+	}
+
+	// A atualização condicional garante que o token seja usado uma única vez
+	result := database.DB.Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", record.ID, time.Now()).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf("erro ao consumir token de redefinição: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return 0, ErrPasswordResetTokenInvalid
+	}
+
+	return record.UserID, nil
+}
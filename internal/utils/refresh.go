@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/settings"
+)
+
+// Prefixo das chaves usadas no Redis para os refresh tokens
+const refreshTokenPrefix = "refresh_token:"
+
+var (
+	// ErrRefreshTokenInvalid indica um refresh token inexistente, expirado ou de família revogada
+	ErrRefreshTokenInvalid = errors.New("refresh token inválido ou expirado")
+	// ErrRefreshTokenReused indica que um refresh token já utilizado foi apresentado novamente
+	ErrRefreshTokenReused = errors.New("refresh token reutilizado: sessão revogada")
+)
+
+// IssueTokenPair abre uma nova sessão para o usuário e gera o primeiro par de tokens dela.
+// Cada sessão corresponde a uma família de refresh tokens.
+func IssueTokenPair(user models.User, info SessionInfo) (*models.TokenResponse, error) {
+	sessionID, err := createSession(user.ID, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return issueTokens(user, info.OrganizationID, sessionID)
+}
+
+// RotateRefreshToken consome o refresh token informado e retorna o ID do usuário e a família a que ele pertence.
+// Se o token já tiver sido utilizado, toda a família é revogada e ErrRefreshTokenReused é retornado.
+func RotateRefreshToken(refreshToken string) (uint, string, error) {
+	key := refreshTokenPrefix + hashToken(refreshToken)
+
+	record, err := database.RedisClient.HGetAll(database.Ctx, key).Result()
+	if err != nil {
+		return 0, "", fmt.Errorf("erro ao buscar refresh token: %v", err)
+	}
+	if len(record) == 0 {
+		return 0, "", ErrRefreshTokenInvalid
+	}
+
+	family := record["family"]
+	userID, err := strconv.ParseUint(record["user_id"], 10, 64)
+	if err != nil {
+		return 0, "", ErrRefreshTokenInvalid
+	}
+
+	// Uma sessão revogada invalida todos os tokens da família
+	active, err := sessionExists(family)
+	if err != nil {
+		return 0, "", err
+	}
+	if !active {
+		return 0, "", ErrRefreshTokenInvalid
+	}
+
+	// Marca o token como utilizado; HSETNX garante que apenas uma requisição consiga consumi-lo
+	consumed, err := database.RedisClient.HSetNX(database.Ctx, key, "used_at", time.Now().Unix()).Result()
+	if err != nil {
+		return 0, "", fmt.Errorf("erro ao consumir refresh token: %v", err)
+	}
+	if !consumed {
+		if err := RevokeSession(family); err != nil {
+			return 0, "", err
+		}
+		return 0, "", ErrRefreshTokenReused
+	}
+
+	return uint(userID), family, nil
+}
+
+// RefreshTokenPair emite um novo par de tokens dentro de uma sessão existente, renovando o seu TTL.
+// Os tokens continuam na organização em que a sessão foi aberta.
+func RefreshTokenPair(user models.User, sessionID string) (*models.TokenResponse, error) {
+	if err := touchSession(user.ID, sessionID); err != nil {
+		return nil, err
+	}
+
+	session, err := GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	return issueTokens(user, session.OrganizationID, sessionID)
+}
+
+// issueTokens gera o access token e um novo refresh token pertencente à família informada
+func issueTokens(user models.User, organizationID uint, family string) (*models.TokenResponse, error) {
+	config := settings.LoadSettings()
+
+	accessToken, err := generateAccessToken(user, organizationID, family)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	// O registro do token usado é mantido até expirar para permitir a detecção de reutilização
+	key := refreshTokenPrefix + hashToken(refreshToken)
+	pipe := database.RedisClient.TxPipeline()
+	pipe.HSet(database.Ctx, key, "user_id", user.ID, "family", family)
+	pipe.Expire(database.Ctx, key, config.JWT.RefreshTokenTTL)
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		return nil, fmt.Errorf("erro ao salvar refresh token no Redis: %v", err)
+	}
+
+	return &models.TokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(config.JWT.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// randomToken gera um valor aleatório opaco codificado em base64 URL-safe
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("erro ao gerar token aleatório: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken retorna o SHA-256 do token; os tokens opacos nunca são armazenados em texto puro
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrRoleInUse indica que a role ainda está atribuída a usuários ou grupos
+	ErrRoleInUse = errors.New("a role ainda possui usuários ou grupos")
+	// ErrRoleHasChildren indica que outras roles herdam da role
+	ErrRoleHasChildren = errors.New("a role possui roles filhas")
+	// ErrLastAdmin indica que a operação deixaria o sistema sem nenhum administrador
+	ErrLastAdmin = errors.New("a operação removeria o último administrador")
+)
+
+// ResolveClaims resolve as capacidades efetivas (role direta e grupos) do usuário das claims, na organização da sessão
+func ResolveClaims(claims *Claims) (*rbac.ResolvedRole, error) {
+	return rbac.ResolveUser(claims.UserID, claims.OrgID, claims.RoleID)
+}
+
+// CountAdmins conta os usuários ativos cujas capacidades efetivas (role direta, considerando a herança, e
+// grupos, como em rbac.ResolveUser) concedem acesso total. Com organizationID 0 conta os administradores
+// globais; caso contrário, os membros administradores da organização. Concessões temporárias não contam:
+// expiram sozinhas e deixariam o sistema sem administrador.
+func CountAdmins(tx *gorm.DB, organizationID uint) (int64, error) {
+	roles, err := rbac.ResolveAll(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	var adminRoles []uint
+	for id, role := range roles {
+		if role.IsAdmin() {
+			adminRoles = append(adminRoles, id)
+		}
+	}
+	if len(adminRoles) == 0 {
+		return 0, nil
+	}
+
+	// Candidatos: quem recebe uma role administrativa diretamente ou por um grupo do mesmo escopo
+	groupMembers := tx.Table("group_users").Select("group_users.user_id").
+		Joins("JOIN groups ON groups.id = group_users.group_id AND groups.deleted_at IS NULL").
+		Joins("JOIN group_roles ON group_roles.group_id = groups.id").
+		Where("group_roles.role_id IN ?", adminRoles)
+	if organizationID == 0 {
+		groupMembers = groupMembers.Where("groups.organization_id IS NULL")
+	} else {
+		groupMembers = groupMembers.Where("groups.organization_id = ?", organizationID)
+	}
+
+	// Contas suspensas, banidas ou pendentes não administram nada
+	var subjects []struct {
+		UserID uint
+		RoleID uint
+	}
+	query := tx.Model(&models.User{}).Select("id AS user_id, role_id").
+		Where("status = ?", models.UserActive).
+		Where("role_id IN ? OR id IN (?)", adminRoles, groupMembers)
+	if organizationID != 0 {
+		query = tx.Model(&models.Membership{}).Select("user_id, role_id").
+			Where("organization_id = ?", organizationID).
+			Where("user_id IN (?)", tx.Model(&models.User{}).Select("id").Where("status = ?", models.UserActive)).
+			Where("role_id IN ? OR user_id IN (?)", adminRoles, groupMembers)
+	}
+	err = query.Scan(&subjects).Error
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar administradores: %v", err)
+	}
+
+	// Um grupo pode negar capacidades da role direta; o resultado é o das capacidades efetivas
+	var count int64
+	for _, subject := range subjects {
+		role, err := rbac.ResolveUserWith(tx, subject.UserID, organizationID, subject.RoleID)
+		if err != nil {
+			return 0, err
+		}
+		if role.IsAdmin() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// adminGuardLock é o primeiro componente da chave do advisory lock de GuardLastAdmin; o segundo é a organização
+// (0 no escopo global)
+const adminGuardLock = 0x61646d
+
+// GuardLastAdmin executa a alteração na transação e retorna ErrLastAdmin se, depois dela, não restar
+// nenhum administrador (globais ou da organização) onde antes havia. Quem chama deve desfazer a
+// transação ao receber o erro.
+//
+// As alterações protegidas do mesmo escopo são serializadas por um advisory lock mantido até o fim da
+// transação: sem ele, duas transações concorrentes removendo os dois últimos administradores veriam, cada
+// uma, um administrador restante e ambas seriam confirmadas.
+func GuardLastAdmin(tx *gorm.DB, organizationID uint, change func() error) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", adminGuardLock, int32(organizationID)).Error; err != nil {
+		return fmt.Errorf("erro ao bloquear a verificação de administradores: %v", err)
+	}
+
+	before, err := CountAdmins(tx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	after, err := CountAdmins(tx, organizationID)
+	if err != nil {
+		return err
+	}
+	if before > 0 && after == 0 {
+		return ErrLastAdmin
+	}
+	return nil
+}
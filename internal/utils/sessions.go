@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/settings"
+)
+
+// Prefixos das chaves usadas no Redis para as sessões
+const (
+	sessionPrefix      = "session:"
+	userSessionsPrefix = "user_sessions:"
+)
+
+// SessionInfo reúne os dados do dispositivo que abriu a sessão e a organização em que ela foi aberta
+type SessionInfo struct {
+	IP             string
+	UserAgent      string
+	OrganizationID uint
+}
+
+// SessionInfoFromRequest extrai o IP e o User-Agent da requisição
+func SessionInfoFromRequest(r *http.Request) SessionInfo {
+	return SessionInfo{
+		IP:        ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// ClientIP retorna o IP do cliente. Os cabeçalhos X-Forwarded-For e X-Real-IP só são
+// considerados quando APP_TRUST_PROXY está habilitado, pois podem ser forjados pelo cliente.
+func ClientIP(r *http.Request) string {
+	if settings.LoadSettings().App.TrustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return strings.TrimSpace(realIP)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// GetSession retorna a sessão com o ID informado ou nil se ela não existir
+func GetSession(sessionID string) (*models.Session, error) {
+	record, err := database.RedisClient.HGetAll(database.Ctx, sessionPrefix+sessionID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar sessão: %v", err)
+	}
+	if len(record) == 0 {
+		return nil, nil
+	}
+
+	userID, _ := strconv.ParseUint(record["user_id"], 10, 64)
+	organizationID, _ := strconv.ParseUint(record["organization_id"], 10, 64)
+	createdAt, _ := strconv.ParseInt(record["created_at"], 10, 64)
+	lastUsedAt, _ := strconv.ParseInt(record["last_used_at"], 10, 64)
+
+	return &models.Session{
+		ID:             sessionID,
+		UserID:         uint(userID),
+		OrganizationID: uint(organizationID),
+		IP:             record["ip"],
+		UserAgent:      record["user_agent"],
+		CreatedAt:      time.Unix(createdAt, 0).UTC(),
+		LastUsedAt:     time.Unix(lastUsedAt, 0).UTC(),
+	}, nil
+}
+
+// ListUserSessions retorna as sessões ativas do usuário, da mais recente para a mais antiga
+func ListUserSessions(userID uint) ([]models.Session, error) {
+	sessionsKey := userSessionsKey(userID)
+
+	ids, err := database.RedisClient.SMembers(database.Ctx, sessionsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar sessões do usuário: %v", err)
+	}
+
+	sessions := []models.Session{}
+	for _, id := range ids {
+		session, err := GetSession(id)
+		if err != nil {
+			return nil, err
+		}
+		// Sessões expiradas são removidas do índice
+		if session == nil {
+			database.RedisClient.SRem(database.Ctx, sessionsKey, id)
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// RevokeSession revoga a sessão: os refresh tokens da família e os access tokens emitidos nela deixam de valer
+func RevokeSession(sessionID string) error {
+	session, err := GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return nil
+	}
+
+	pipe := database.RedisClient.TxPipeline()
+	pipe.Del(database.Ctx, sessionPrefix+sessionID)
+	pipe.SRem(database.Ctx, userSessionsKey(session.UserID), sessionID)
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		return fmt.Errorf("erro ao revogar sessão: %v", err)
+	}
+	return nil
+}
+
+// RevokeUserSessions revoga todas as sessões do usuário
+func RevokeUserSessions(userID uint) error {
+	sessionsKey := userSessionsKey(userID)
+
+	ids, err := database.RedisClient.SMembers(database.Ctx, sessionsKey).Result()
+	if err != nil {
+		return fmt.Errorf("erro ao buscar sessões do usuário: %v", err)
+	}
+
+	keys := []string{sessionsKey}
+	for _, id := range ids {
+		keys = append(keys, sessionPrefix+id)
+	}
+	if err := database.RedisClient.Del(database.Ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("erro ao revogar sessões do usuário: %v", err)
+	}
+	return nil
+}
+
+// createSession registra uma nova sessão e a adiciona ao índice de sessões do usuário
+func createSession(userID uint, info SessionInfo) (string, error) {
+	sessionID, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	config := settings.LoadSettings()
+	now := time.Now().Unix()
+	key := sessionPrefix + sessionID
+	sessionsKey := userSessionsKey(userID)
+
+	pipe := database.RedisClient.TxPipeline()
+	pipe.HSet(database.Ctx, key,
+		"user_id", userID,
+		"organization_id", info.OrganizationID,
+		"ip", info.IP,
+		"user_agent", info.UserAgent,
+		"created_at", now,
+		"last_used_at", now,
+	)
+	pipe.Expire(database.Ctx, key, config.JWT.RefreshTokenTTL)
+	pipe.SAdd(database.Ctx, sessionsKey, sessionID)
+	pipe.Expire(database.Ctx, sessionsKey, config.JWT.RefreshTokenTTL)
+	if _, err := pipe.Exec(database.Ctx); err != nil {
+		return "", fmt.Errorf("erro ao registrar sessão: %v", err)
+	}
+	return sessionID, nil
+}
+
+// touchScript atualiza a sessão apenas se ela ainda existir, para não recriar uma sessão revogada
+var touchScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[1], "last_used_at", ARGV[1])
+redis.call("EXPIRE", KEYS[1], ARGV[2])
+redis.call("EXPIRE", KEYS[2], ARGV[2])
+return 1
+`)
+
+// touchSession registra o uso da sessão e renova o seu TTL
+func touchSession(userID uint, sessionID string) error {
+	ttl := int64(settings.LoadSettings().JWT.RefreshTokenTTL.Seconds())
+	keys := []string{sessionPrefix + sessionID, userSessionsKey(userID)}
+
+	touched, err := touchScript.Run(database.Ctx, database.RedisClient, keys, time.Now().Unix(), ttl).Int()
+	if err != nil {
+		return fmt.Errorf("erro ao renovar sessão: %v", err)
+	}
+	if touched == 0 {
+		return ErrRefreshTokenInvalid
+	}
+	return nil
+}
+
+// sessionExists indica se a sessão ainda está ativa
+func sessionExists(sessionID string) (bool, error) {
+	exists, err := database.RedisClient.Exists(database.Ctx, sessionPrefix+sessionID).Result()
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar sessão: %v", err)
+	}
+	return exists > 0, nil
+}
+
+func userSessionsKey(userID uint) string {
+	return userSessionsPrefix + strconv.FormatUint(uint64(userID), 10)
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+)
+
+// userStatusPrefix é o prefixo das chaves que marcam no Redis as contas que não estão ativas.
+// A marca é consultada a cada requisição autenticada, sem ir ao banco.
+const userStatusPrefix = "user_status:"
+
+// SyncUserStatus registra a situação da conta no Redis: contas ativas não têm marca
+func SyncUserStatus(userID uint, status string) error {
+	key := fmt.Sprintf("%s%d", userStatusPrefix, userID)
+	var err error
+	if status == models.UserActive {
+		err = database.RedisClient.Del(database.Ctx, key).Err()
+	} else {
+		err = database.RedisClient.Set(database.Ctx, key, status, 0).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao registrar a situação da conta: %v", err)
+	}
+	return nil
+}
+
+// SyncInactiveUsers grava no Redis a marca de todas as contas que não estão ativas e remove as marcas que não
+// correspondem mais ao banco. É executada na inicialização: um Redis vazio ou restaurado não libera contas
+// suspensas, e o middleware continua sem consultar o banco a cada requisição.
+func SyncInactiveUsers() (int, error) {
+	var users []models.User
+	if err := database.DB.Select("id", "status").Where("status <> ?", models.UserActive).Find(&users).Error; err != nil {
+		return 0, fmt.Errorf("erro ao buscar contas inativas: %v", err)
+	}
+
+	inactive := make(map[string]bool, len(users))
+	for _, user := range users {
+		if err := SyncUserStatus(user.ID, user.Status); err != nil {
+			return 0, err
+		}
+		inactive[fmt.Sprintf("%s%d", userStatusPrefix, user.ID)] = true
+	}
+
+	iter := database.RedisClient.Scan(database.Ctx, 0, userStatusPrefix+"*", 100).Iterator()
+	for iter.Next(database.Ctx) {
+		if inactive[iter.Val()] {
+			continue
+		}
+		if err := database.RedisClient.Del(database.Ctx, iter.Val()).Err(); err != nil {
+			return 0, fmt.Errorf("erro ao remover marca de situação: %v", err)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("erro ao percorrer marcas de situação: %v", err)
+	}
+	return len(users), nil
+}
+
+// InactiveStatus retorna a situação da conta quando ela não está ativa, ou "" para contas ativas
+func InactiveStatus(userID uint) (string, error) {
+	status, err := database.RedisClient.Get(database.Ctx, fmt.Sprintf("%s%d", userStatusPrefix, userID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("erro ao verificar a situação da conta: %v", err)
+	}
+	return status, nil
+}
+
+// InactiveAccountMessage descreve o motivo pelo qual uma conta que não está ativa é recusada
+func InactiveAccountMessage(status string) string {
+	switch status {
+	case models.UserPending:
+		return "Conta pendente de ativação"
+	case models.UserSuspended:
+		return "Conta suspensa"
+	case models.UserBanned:
+		return "Conta banida"
+	default:
+		return "Conta inativa"
+	}
+}
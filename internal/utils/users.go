@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"gorm.io/gorm"
+)
+
+// uniqueViolation é o código de erro do PostgreSQL para violação de unicidade
+const uniqueViolation = "23505"
+
+// IsUniqueViolation indica se o erro do banco é uma violação de índice ou restrição única
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// PurgeUser remove definitivamente um usuário excluído e os registros que dependem dele.
+// Usuários não excluídos não são alcançados.
+func PurgeUser(tx *gorm.DB, userID uint) error {
+	dependents := []interface{}{
+		&models.Membership{},
+		&models.RoleGrant{},
+		&models.MFARecoveryCode{},
+		&models.PasswordResetToken{},
+	}
+	for _, model := range dependents {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Exec("DELETE FROM group_users WHERE user_id = ?", userID).Error; err != nil {
+		return err
+	}
+	// As concessões de outros usuários permanecem, sem a referência a quem as solicitou ou decidiu
+	if err := tx.Unscoped().Model(&models.RoleGrant{}).Where("requested_by_id = ?", userID).Update("requested_by_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.RoleGrant{}).Where("decided_by_id = ?", userID).Update("decided_by_id", nil).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("deleted_at IS NOT NULL").Delete(&models.User{}, userID).Error
+}
+
+// PurgeDeletedUsers remove definitivamente os usuários excluídos antes do instante informado
+func PurgeDeletedUsers(before time.Time) (int, error) {
+	var ids []uint
+	if err := database.DB.Unscoped().Model(&models.User{}).Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, id := range ids {
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return PurgeUser(tx, id)
+		})
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// StartUserPurge remove periodicamente os usuários que estão na lixeira há mais que retention,
+// até o contexto ser cancelado. Com interval 0 nada é feito.
+func StartUserPurge(ctx context.Context, interval, retention time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			purged, err := PurgeDeletedUsers(time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("Erro ao remover usuários excluídos: %v", err)
+			} else if purged > 0 {
+				log.Printf("%d usuário(s) excluído(s) removido(s) definitivamente", purged)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -2,17 +2,24 @@ package utils
 
 import (
 	"fmt"
-	"os"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/go-redis/redis/v8"
 	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/keyring"
 	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/jeffemart/Gotham/internal/settings"
 )
 
-// Define a chave secreta (deve ser configurada como variável de ambiente)
-var secretKey = []byte(os.Getenv("APP_KEY"))
+// Keyring com as chaves de assinatura dos tokens (carregado sob demanda)
+var (
+	keyringMu      sync.RWMutex
+	currentKeyring *keyring.Keyring
+)
 
 // Defina um tipo específico para a chave no contexto
 type RoleKeyType string
@@ -21,11 +28,17 @@ type RoleKeyType string
 // Isso será usado para armazenar os Claims no contexto
 const RoleKey RoleKeyType = "RoleKey"
 
+// TokenKey armazena no contexto o token bruto da requisição autenticada
+const TokenKey RoleKeyType = "TokenKey"
+
 // Claims personalizados para incluir a role do usuário
 type Claims struct {
+	UserID      uint     `json:"user_id"`
 	Email       string   `json:"email"`
 	RoleID      uint     `json:"role_id"`
+	OrgID       uint     `json:"org_id,omitempty"` // Organização da sessão; 0 no escopo global
 	Permissions []string `json:"permissions"`
+	SessionID   string   `json:"sid,omitempty"`
 	jwt.StandardClaims
 }
 
@@ -41,67 +54,158 @@ func SaveTokenToRedis(tokenString string, expiration time.Duration) error {
 
 // GenerateTokenWithPermissions gera um token JWT com as permissões do usuário
 func GenerateTokenWithPermissions(user models.User) (string, error) {
-	// Carregar a role do usuário e suas permissões
-	var role models.Role
-	if err := database.DB.Preload("Permissions").First(&role, user.RoleID).Error; err != nil {
-		return "", fmt.Errorf("erro ao buscar role do usuário: %v", err)
+	return generateAccessToken(user, 0, "")
+}
+
+// generateAccessToken gera o access token vinculado à sessão (família de refresh tokens) informada.
+// Em uma organização vale a role do usuário nela; no escopo global, a role do próprio usuário.
+func generateAccessToken(user models.User, organizationID uint, sessionID string) (string, error) {
+	roleID := user.RoleID
+	if organizationID != 0 {
+		membership, err := FindMembership(user.ID, organizationID)
+		if err != nil {
+			return "", err
+		}
+		roleID = membership.RoleID
 	}
 
-	// Criar uma lista de permissões
-	var permissions []string
-	for _, permission := range role.Permissions {
-		permissions = append(permissions, permission.Name)
+	// Carregar a role do usuário com as permissões próprias, herdadas e recebidas dos grupos
+	role, err := rbac.ResolveUser(user.ID, organizationID, roleID)
+	if err != nil {
+		return "", fmt.Errorf("erro ao buscar role do usuário: %v", err)
 	}
+	permissions := role.Permissions
+
+	// O access token tem vida curta; a renovação é feita pelo refresh token
+	ttl := settings.LoadSettings().JWT.AccessTokenTTL
 
 	// Definir as claims do token
 	claims := Claims{
+		UserID:      user.ID,
 		Email:       user.Email,
 		RoleID:      role.ID,
+		OrgID:       organizationID,
 		Permissions: permissions,
+		SessionID:   sessionID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
+			ExpiresAt: time.Now().Add(ttl).Unix(),
 		},
 	}
 
 	// Gerar o token JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(secretKey)
+	tokenString, err := SignClaims(claims)
 	if err != nil {
-		return "", fmt.Errorf("erro ao assinar o token: %v", err)
+		return "", err
 	}
 
-	// Salvar o token no Redis com TTL de 24 horas
-	if err := SaveTokenToRedis(tokenString, time.Hour*24); err != nil {
+	// Salvar o token no Redis com o mesmo TTL do access token
+	if err := SaveTokenToRedis(tokenString, ttl); err != nil {
 		return "", err
 	}
 
 	return tokenString, nil
 }
 
+// LoadKeyring carrega (ou recarrega) as chaves do diretório configurado.
+// Se o diretório ainda não tiver chaves, uma chave inicial é gerada com o algoritmo configurado.
+func LoadKeyring() (*keyring.Keyring, error) {
+	config := settings.LoadSettings()
+
+	ring, err := keyring.Load(config.JWT.KeysDir)
+	if err != nil {
+		return nil, err
+	}
+	if ring.Active == nil {
+		key, err := keyring.Rotate(config.JWT.KeysDir, config.JWT.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Nenhuma chave de assinatura encontrada; chave %s (%s) gerada em %s", key.ID, key.Algorithm, config.JWT.KeysDir)
+
+		if ring, err = keyring.Load(config.JWT.KeysDir); err != nil {
+			return nil, err
+		}
+	}
+
+	keyringMu.Lock()
+	currentKeyring = ring
+	keyringMu.Unlock()
+
+	return ring, nil
+}
+
+// Keyring retorna o keyring em uso, carregando-o na primeira chamada
+func Keyring() (*keyring.Keyring, error) {
+	keyringMu.RLock()
+	ring := currentKeyring
+	keyringMu.RUnlock()
+
+	if ring != nil {
+		return ring, nil
+	}
+	return LoadKeyring()
+}
+
+// SignClaims assina as claims com a chave ativa, identificando-a no cabeçalho kid
+func SignClaims(claims jwt.Claims) (string, error) {
+	ring, err := Keyring()
+	if err != nil {
+		return "", fmt.Errorf("erro ao carregar chaves de assinatura: %v", err)
+	}
+
+	token := jwt.NewWithClaims(ring.Active.SigningMethod(), claims)
+	token.Header["kid"] = ring.Active.ID
+
+	tokenString, err := token.SignedString(ring.Active.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("erro ao assinar o token: %v", err)
+	}
+	return tokenString, nil
+}
+
 // Função para fazer o parse e validação do token JWT
 func ParseToken(tokenString string) (*jwt.Token, *Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("token inválido: método de assinatura inválido")
-		}
-		return secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, verificationKey)
 
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// Tokens de ação (com audience, como a verificação de e-mail) não são access tokens
 	claims, ok := token.Claims.(*Claims)
-	if ok && token.Valid {
+	if ok && token.Valid && claims.Audience == "" {
 		return token, claims, nil
 	}
 
 	return nil, nil, fmt.Errorf("token inválido")
 }
 
+// verificationKey localiza pelo kid a chave pública (ativa ou aposentada) que verifica o token
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	ring, err := Keyring()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar chaves de assinatura: %v", err)
+	}
+
+	key, ok := ring.Key(kid)
+	if !ok {
+		return nil, fmt.Errorf("token inválido: chave de assinatura desconhecida")
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("token inválido: método de assinatura inválido")
+	}
+
+	return key.PublicKey(), nil
+}
+
 // Função para validar o token, incluindo verificação no Redis
 func ValidateToken(tokenString string) (*Claims, error) {
 	_, claims, err := ParseToken(tokenString)
+	if ve, ok := err.(*jwt.ValidationError); ok && ve.Errors&jwt.ValidationErrorExpired != 0 {
+		return nil, fmt.Errorf("token expirado")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("token inválido: %v", err)
 	}
@@ -117,6 +221,14 @@ func ValidateToken(tokenString string) (*Claims, error) {
 		return nil, fmt.Errorf("token revogado ou inválido")
 	}
 
+	// Tokens vinculados a uma sessão deixam de valer quando a sessão é revogada
+	if claims.SessionID != "" {
+		active, err := sessionExists(claims.SessionID)
+		if err != nil || !active {
+			return nil, fmt.Errorf("sessão revogada")
+		}
+	}
+
 	return claims, nil
 }
 
@@ -7,6 +7,36 @@ import (
 	"github.com/jeffemart/Gotham/internal/models"
 )
 
+// roleNameIndexes substitui a restrição de nome único das roles por índices parciais por escopo
+var roleNameIndexes = []string{
+	`ALTER TABLE roles DROP CONSTRAINT IF EXISTS uni_roles_name`,
+	`ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_name_key`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_global_name ON roles (name) WHERE organization_id IS NULL AND deleted_at IS NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_organization_name ON roles (organization_id, name) WHERE organization_id IS NOT NULL AND deleted_at IS NULL`,
+}
+
+// groupNameIndexes garante nomes de grupos únicos entre os grupos globais e dentro de cada organização
+var groupNameIndexes = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_global_name ON groups (name) WHERE organization_id IS NULL AND deleted_at IS NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_organization_name ON groups (organization_id, name) WHERE organization_id IS NOT NULL AND deleted_at IS NULL`,
+}
+
+// permissionNameIndexes restringe a unicidade do nome às permissões não removidas, para que o nome de uma
+// permissão removida possa ser usado novamente
+var permissionNameIndexes = []string{
+	`ALTER TABLE permissions DROP CONSTRAINT IF EXISTS uni_permissions_name`,
+	`ALTER TABLE permissions DROP CONSTRAINT IF EXISTS permissions_name_key`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_live_name ON permissions (name) WHERE deleted_at IS NULL`,
+}
+
+// userEmailIndexes restringe a unicidade do e-mail aos usuários não excluídos, para que o e-mail de um
+// usuário na lixeira possa ser cadastrado novamente
+var userEmailIndexes = []string{
+	`ALTER TABLE users DROP CONSTRAINT IF EXISTS uni_users_email`,
+	`ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_live_email ON users (email) WHERE deleted_at IS NULL`,
+}
+
 // Run executa a migração do banco de dados
 // Run executa as migrações do banco de dados
 func Run() error {
@@ -16,11 +46,40 @@ func Run() error {
 	log.Println("Iniciando migrações...")
 
 	// Executar a migração da tabela `users`
-	if err := db.AutoMigrate(&models.User{}, &models.Role{}, &models.Permission{}, &models.RolePermission{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.Role{}, &models.Permission{}, &models.RolePermission{}, &models.MFARecoveryCode{}, &models.PasswordResetToken{}, &models.Organization{}, &models.Membership{}, &models.Group{}, &models.RoleGrant{}); err != nil {
 		log.Printf("Erro ao executar migração da tabela `users`: %v\n", err)
 		return err
 	}
 
+	// O nome das roles deixou de ser único globalmente: é único entre as roles globais e dentro de cada organização
+	for _, statement := range roleNameIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Printf("Erro ao criar índices da tabela `roles`: %v\n", err)
+			return err
+		}
+	}
+
+	for _, statement := range groupNameIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Printf("Erro ao criar índices da tabela `groups`: %v\n", err)
+			return err
+		}
+	}
+
+	for _, statement := range permissionNameIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Printf("Erro ao criar índices da tabela `permissions`: %v\n", err)
+			return err
+		}
+	}
+
+	for _, statement := range userEmailIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Printf("Erro ao criar índices da tabela `users`: %v\n", err)
+			return err
+		}
+	}
+
 	log.Println("Migrações concluídas com sucesso!")
 	return nil
 }
@@ -1,18 +1,142 @@
 package validator
 
-import "regexp"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var emailPattern = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+// minPersonalTokenLength é o tamanho mínimo de um trecho do e-mail ou do nome para ser procurado na senha
+const minPersonalTokenLength = 3
+
+// MaxPasswordBytes é o tamanho máximo da senha em bytes: o bcrypt recusa senhas mais longas
+const MaxPasswordBytes = 72
 
 // EmailValidator valida o formato do email
 func EmailValidator(email string) bool {
-	pattern := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-	re := regexp.MustCompile(pattern)
-	return re.MatchString(email)
+	return emailPattern.MatchString(email)
 }
 
 // PasswordValidator valida a força da senha
 func PasswordValidator(password string) bool {
 	// Mínimo 8 caracteres, pelo menos uma letra maiúscula, uma minúscula e um número
-	pattern := `^(?=.*[a-z])(?=.*[A-Z])(?=.*\d)[a-zA-Z\d]{8,}$`
-	re := regexp.MustCompile(pattern)
-	return re.MatchString(password)
+	return len(DefaultPasswordPolicy.Validate(password)) == 0
+}
+
+// PasswordPolicy define as regras de força de senha
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	BannedWords   []string
+}
+
+// DefaultPasswordPolicy é a política usada por PasswordValidator
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:    8,
+	RequireUpper: true,
+	RequireLower: true,
+	RequireDigit: true,
+}
+
+// Validate retorna as regras violadas pela senha (vazio se ela for válida).
+// personal recebe dados do usuário, como e-mail e nome, que não podem aparecer na senha.
+func (p PasswordPolicy) Validate(password string, personal ...string) []string {
+	var violations []string
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("A senha deve ter pelo menos %d caracteres", p.MinLength))
+	}
+	// Vale para qualquer política, pois é o limite do hash; caracteres acentuados ocupam mais de um byte
+	if len(password) > MaxPasswordBytes {
+		violations = append(violations, fmt.Sprintf("A senha deve ter no máximo %d bytes", MaxPasswordBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c) || unicode.IsSpace(c):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, "A senha deve conter pelo menos uma letra maiúscula")
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, "A senha deve conter pelo menos uma letra minúscula")
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, "A senha deve conter pelo menos um número")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, "A senha deve conter pelo menos um símbolo")
+	}
+
+	lower := strings.ToLower(password)
+	for _, word := range p.BannedWords {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" && strings.Contains(lower, word) {
+			violations = append(violations, "A senha contém uma palavra não permitida")
+			break
+		}
+	}
+
+	for _, token := range personalTokens(personal) {
+		if strings.Contains(lower, token) {
+			violations = append(violations, "A senha não pode conter o seu e-mail ou nome")
+			break
+		}
+	}
+
+	return violations
+}
+
+// personalTokens separa o e-mail (completo e parte local) e as palavras do nome em trechos a procurar na senha
+func personalTokens(values []string) []string {
+	var tokens []string
+	add := func(token string) {
+		if utf8.RuneCountInString(token) >= minPersonalTokenLength {
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if local, _, found := strings.Cut(value, "@"); found {
+			add(value)
+			add(local)
+			continue
+		}
+		for _, part := range strings.Fields(value) {
+			add(part)
+		}
+	}
+	return tokens
+}
+
+// Errors agrupa mensagens de validação por campo
+type Errors map[string][]string
+
+// Add registra as mensagens de erro do campo informado
+func (e Errors) Add(field string, messages ...string) {
+	if len(messages) > 0 {
+		e[field] = append(e[field], messages...)
+	}
+}
+
+// Empty indica se nenhum erro foi registrado
+func (e Errors) Empty() bool {
+	return len(e) == 0
 }
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"bytes"
+	"io"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/jeffemart/Gotham/internal/mailer"
+)
+
+// UseFileMailer direciona os e-mails da aplicação para um diretório temporário do teste
+func UseFileMailer(t *testing.T) string {
+	dir := t.TempDir()
+	previous := mailer.Default
+	mailer.Default = &mailer.FileMailer{Dir: dir, From: "test@gotham.local"}
+	t.Cleanup(func() { mailer.Default = previous })
+	return dir
+}
+
+// ReadMails aguarda os envios em segundo plano e retorna as mensagens gravadas no diretório, em ordem de envio,
+// com o corpo decodificado
+func ReadMails(t *testing.T, dir string) []*mail.Message {
+	mailer.Wait()
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+
+	var messages []*mail.Message
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		msg, err := mail.ReadMessage(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg.Body = quotedprintable.NewReader(msg.Body)
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// MailBody lê o corpo decodificado da mensagem
+func MailBody(t *testing.T, msg *mail.Message) string {
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
@@ -1,8 +1,16 @@
 package helpers
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
 	"github.com/jeffemart/Gotham/internal/database"
 	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func SetupTestDB() {
@@ -15,15 +23,97 @@ func CleanupTestDB() {
 	database.DB.Exec("DELETE FROM roles")
 	database.DB.Exec("DELETE FROM permissions")
 	database.DB.Exec("DELETE FROM role_permissions")
+	database.DB.Exec("DELETE FROM mfa_recovery_codes")
+	database.DB.Exec("DELETE FROM password_reset_tokens")
+	database.DB.Exec("DELETE FROM memberships")
+	database.DB.Exec("DELETE FROM organizations")
+	database.DB.Exec("DELETE FROM group_users")
+	database.DB.Exec("DELETE FROM group_roles")
+	database.DB.Exec("DELETE FROM groups")
+	database.DB.Exec("DELETE FROM role_grants")
+	CleanupLockouts()
+
+	// As marcas de contas inativas também sobrevivem no Redis
+	if keys, err := database.RedisClient.Keys(database.Ctx, "user_status:*").Result(); err == nil && len(keys) > 0 {
+		database.RedisClient.Del(database.Ctx, keys...)
+	}
+
+	// As roles são recriadas a cada teste; o cache não pode devolver as do teste anterior
+	rbac.DefaultCache.Purge()
+}
+
+// CleanupLockouts remove os contadores e bloqueios de login, que sobrevivem entre os testes no Redis
+func CleanupLockouts() {
+	if keys, err := database.RedisClient.Keys(database.Ctx, "login_*").Result(); err == nil && len(keys) > 0 {
+		database.RedisClient.Del(database.Ctx, keys...)
+	}
 }
 
 func CreateTestUser() models.User {
+	role := models.Role{Name: "admin", Capabilities: []string{"*"}}
+	database.DB.Create(&role)
+
+	// A senha é armazenada com bcrypt, como em handlers.CreateUser
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("Test123!"), bcrypt.DefaultCost)
+	verifiedAt := time.Now()
 	user := models.User{
-		Name:     "Test User",
-		Email:    "test@example.com",
-		Password: "Test123!",
-		RoleID:   1,
+		Name:       "Test User",
+		Email:      "test@example.com",
+		Password:   string(hashedPassword),
+		RoleID:     role.ID,
+		VerifiedAt: &verifiedAt,
 	}
 	database.DB.Create(&user)
 	return user
 }
+
+// CreateUserWithRole cria um usuário verificado com a role informada e a senha "Test123!"
+func CreateUserWithRole(email string, roleID uint) models.User {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("Test123!"), bcrypt.DefaultCost)
+	verifiedAt := time.Now()
+	user := models.User{
+		Name:       "Test Member",
+		Email:      email,
+		Password:   string(hashedPassword),
+		RoleID:     roleID,
+		VerifiedAt: &verifiedAt,
+	}
+	database.DB.Create(&user)
+	return user
+}
+
+// Login autentica o usuário nas rotas informadas e retorna os tokens e o status da resposta
+func Login(handler http.Handler, email, password string) (models.TokenResponse, int) {
+	return LoginOrganization(handler, email, password, 0)
+}
+
+// LoginOrganization é como Login, mas abre a sessão na organização informada
+func LoginOrganization(handler http.Handler, email, password string, organizationID uint) (models.TokenResponse, int) {
+	payload, _ := json.Marshal(models.LoginRequest{Email: email, Password: password, OrganizationID: organizationID})
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var tokens models.TokenResponse
+	json.NewDecoder(w.Body).Decode(&tokens)
+	return tokens, w.Code
+}
+
+// Request envia a requisição às rotas informadas autenticada com o token (sem cabeçalho quando token é vazio).
+// Um corpo do tipo string é enviado como está, para exercitar JSON malformado; os demais são serializados.
+func Request(handler http.Handler, token, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if raw, ok := body.(string); ok {
+		payload = []byte(raw)
+	} else if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/handlers"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/policy"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizationCheck(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	decide := func(token string, request models.AuthzCheckRequest) policy.Decision {
+		w := helpers.Request(r, token, "POST", "/authz/check", request)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var decision policy.Decision
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&decision))
+		return decision
+	}
+
+	tokens, code := helpers.Login(r, admin.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	adminToken := tokens.Token
+
+	w := helpers.Request(r, adminToken, "POST", "/admin/roles", models.RoleRequest{Name: "leitor", Capabilities: []string{models.CapabilityReadOwnUser, "!delete:user"}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var reader models.Role
+	json.NewDecoder(w.Body).Decode(&reader)
+
+	dave := helpers.CreateUserWithRole("dave@example.com", reader.ID)
+	tokens, code = helpers.Login(r, dave.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	daveToken := tokens.Token
+
+	// Sem sujeito, a decisão vale para o próprio usuário
+	own := &models.AuthzResource{Type: "user", OwnerID: dave.ID}
+	decision := decide(daveToken, models.AuthzCheckRequest{AuthzCheck: models.AuthzCheck{Action: models.CapabilityReadUser, Resource: own}})
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, policy.ScopeOwn, decision.Scope)
+
+	other := &models.AuthzResource{Type: "user", OwnerID: admin.ID}
+	decision = decide(daveToken, models.AuthzCheckRequest{AuthzCheck: models.AuthzCheck{Action: models.CapabilityReadUser, Resource: other}})
+	assert.False(t, decision.Allowed)
+	assert.NotEmpty(t, decision.Reason)
+
+	// Verificar outro sujeito exige check:authz
+	subject := models.AuthzSubject{UserID: admin.ID}
+	w = helpers.Request(r, daveToken, "POST", "/authz/check", models.AuthzCheckRequest{Subject: subject, AuthzCheck: models.AuthzCheck{Action: "read:user"}})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Pelo token ou pelo ID, com o motivo da negação
+	decision = decide(adminToken, models.AuthzCheckRequest{Subject: models.AuthzSubject{Token: daveToken}, AuthzCheck: models.AuthzCheck{Action: models.CapabilityDeleteUser}})
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "!delete:user")
+
+	decision = decide(adminToken, models.AuthzCheckRequest{Subject: models.AuthzSubject{UserID: dave.ID}, AuthzCheck: models.AuthzCheck{Action: models.CapabilityDeleteUser}})
+	assert.False(t, decision.Allowed)
+
+	decision = decide(adminToken, models.AuthzCheckRequest{Subject: models.AuthzSubject{Token: "invalido"}, AuthzCheck: models.AuthzCheck{Action: "read:user"}})
+	assert.False(t, decision.Allowed)
+
+	// Em lote, as decisões seguem a ordem das verificações
+	w = helpers.Request(r, daveToken, "POST", "/authz/check/batch", models.AuthzBatchRequest{Checks: []models.AuthzCheck{
+		{Action: models.CapabilityManageRoles},
+		{Action: models.CapabilityReadUser, Resource: own},
+	}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var batch handlers.AuthzBatchResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&batch))
+	if assert.Len(t, batch.Results, 2) {
+		assert.False(t, batch.Results[0].Allowed)
+		assert.True(t, batch.Results[1].Allowed)
+	}
+
+	w = helpers.Request(r, daveToken, "POST", "/authz/check/batch", models.AuthzBatchRequest{})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
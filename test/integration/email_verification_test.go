@@ -0,0 +1,89 @@
+package integration
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailVerification(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+	mailDir := helpers.UseFileMailer(t)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	// O cadastro envia o link de verificação
+	database.DB.Create(&models.Role{Name: "user"})
+	w := helpers.Request(r, "", "POST", "/users", models.CreateUserRequest{
+		Name:     "Verify User",
+		Email:    "verify@example.com",
+		Password: "V3rify#Gotham",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	messages := helpers.ReadMails(t, mailDir)
+	assert.Len(t, messages, 1)
+
+	token := regexp.MustCompile(`token=([A-Za-z0-9_.-]+)`).FindStringSubmatch(helpers.MailBody(t, messages[0]))
+	assert.Len(t, token, 2)
+
+	// Um token adulterado é recusado
+	assert.Equal(t, http.StatusBadRequest, helpers.Request(r, "", "POST", "/email/verify", models.VerifyEmailRequest{Token: token[1] + "x"}).Code)
+
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/email/verify", models.VerifyEmailRequest{Token: token[1]}).Code)
+
+	var user models.User
+	assert.NoError(t, database.DB.Where("email = ?", "verify@example.com").First(&user).Error)
+	assert.NotNil(t, user.VerifiedAt)
+
+	// Contas verificadas não recebem novos links
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/email/verify/resend", models.ResendVerificationRequest{Email: user.Email}).Code)
+	assert.Len(t, helpers.ReadMails(t, mailDir), 1)
+}
+
+func TestLoginRequiresVerifiedEmail(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+	mailDir := helpers.UseFileMailer(t)
+	t.Setenv("AUTH_REQUIRE_EMAIL_VERIFICATION", "true")
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	database.DB.Create(&models.Role{Name: "user"})
+	w := helpers.Request(r, "", "POST", "/users", models.CreateUserRequest{
+		Name:     "Pending User",
+		Email:    "pending@example.com",
+		Password: "V3rify#Gotham",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Sem a verificação o login é recusado; a situação só é revelada a quem acertou a senha
+	_, code := helpers.Login(r, "pending@example.com", "V3rify#Gotham")
+	assert.Equal(t, http.StatusForbidden, code)
+	_, code = helpers.Login(r, "pending@example.com", "Wrong#Gotham1")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// O reenvio gera um novo link, que libera o login
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/email/verify/resend", models.ResendVerificationRequest{Email: "pending@example.com"}).Code)
+	messages := helpers.ReadMails(t, mailDir)
+	assert.Len(t, messages, 2)
+
+	token := regexp.MustCompile(`token=([A-Za-z0-9_.-]+)`).FindStringSubmatch(helpers.MailBody(t, messages[1]))
+	assert.Len(t, token, 2)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/email/verify", models.VerifyEmailRequest{Token: token[1]}).Code)
+
+	_, code = helpers.Login(r, "pending@example.com", "V3rify#Gotham")
+	assert.Equal(t, http.StatusOK, code)
+}
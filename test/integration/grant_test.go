@@ -0,0 +1,89 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleGrantWorkflow(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+	approver := helpers.CreateUserWithRole("approver@example.com", admin.RoleID)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	login := func(email string) string {
+		tokens, code := helpers.Login(r, email, "Test123!")
+		assert.Equal(t, http.StatusOK, code)
+		return tokens.Token
+	}
+	adminToken := login(admin.Email)
+	approverToken := login(approver.Email)
+
+	w := helpers.Request(r, adminToken, "POST", "/admin/roles", models.RoleRequest{Name: "basico"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var basic models.Role
+	json.NewDecoder(w.Body).Decode(&basic)
+	w = helpers.Request(r, adminToken, "POST", "/admin/roles", models.RoleRequest{Name: "plantao", Capabilities: []string{models.CapabilityManageSessions}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var onCall models.Role
+	json.NewDecoder(w.Body).Decode(&onCall)
+
+	carol := helpers.CreateUserWithRole("carol@example.com", basic.ID)
+	carolToken := login(carol.Email)
+	sessions := fmt.Sprintf("/admin/users/%d/sessions", carol.ID)
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, carolToken, "GET", sessions, nil).Code)
+
+	// O período é validado e não pode passar da duração máxima
+	tooLong := models.GrantRequest{RoleID: onCall.ID, ExpiresAt: time.Now().Add(30 * 24 * time.Hour)}
+	assert.Equal(t, http.StatusUnprocessableEntity, helpers.Request(r, carolToken, "POST", "/me/grants", tooLong).Code)
+
+	requestGrant := func(expiresIn time.Duration) models.GrantResponse {
+		w := helpers.Request(r, carolToken, "POST", "/me/grants", models.GrantRequest{RoleID: onCall.ID, ExpiresAt: time.Now().Add(expiresIn), Reason: "plantão"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var grant models.GrantResponse
+		json.NewDecoder(w.Body).Decode(&grant)
+		assert.Equal(t, models.GrantPending, grant.Status)
+		return grant
+	}
+
+	// A concessão pendente não concede nada
+	grant := requestGrant(time.Hour)
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, carolToken, "GET", sessions, nil).Code)
+
+	// Aprovada por um administrador, vale imediatamente e pode ser revogada
+	approve := fmt.Sprintf("/admin/grants/%d/approve", grant.ID)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "POST", approve, nil).Code)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, approverToken, "POST", approve, nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, carolToken, "GET", sessions, nil).Code)
+
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "POST", fmt.Sprintf("/admin/grants/%d/revoke", grant.ID), nil).Code)
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, carolToken, "GET", sessions, nil).Code)
+
+	// Quem solicita a concessão não pode aprová-la
+	w = helpers.Request(r, adminToken, "POST", "/admin/grants", models.GrantRequest{UserID: carol.ID, RoleID: onCall.ID, ExpiresAt: time.Now().Add(time.Hour)})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	json.NewDecoder(w.Body).Decode(&grant)
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, adminToken, "POST", fmt.Sprintf("/admin/grants/%d/approve", grant.ID), nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, approverToken, "POST", fmt.Sprintf("/admin/grants/%d/reject", grant.ID), nil).Code)
+
+	// A expiração vale sem nenhuma invalidação explícita
+	grant = requestGrant(2 * time.Second)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "POST", fmt.Sprintf("/admin/grants/%d/approve", grant.ID), nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, carolToken, "GET", sessions, nil).Code)
+	time.Sleep(time.Until(grant.ExpiresAt))
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, carolToken, "GET", sessions, nil).Code)
+}
@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupRoles(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	tokens, code := helpers.Login(r, admin.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	adminToken := tokens.Token
+
+	createRole := func(name string, capabilities ...string) models.Role {
+		w := helpers.Request(r, adminToken, "POST", "/admin/roles", models.RoleRequest{Name: name, Capabilities: capabilities})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var role models.Role
+		json.NewDecoder(w.Body).Decode(&role)
+		return role
+	}
+	basic := createRole("basico")
+	manager := createRole("gestor", models.CapabilityManageRoles)
+
+	carol := helpers.CreateUserWithRole("carol@example.com", basic.ID)
+	tokens, code = helpers.Login(r, carol.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	carolToken := tokens.Token
+
+	// Sem grupos valem apenas as capacidades da role direta
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, carolToken, "GET", "/admin/roles", nil).Code)
+
+	w := helpers.Request(r, adminToken, "POST", "/admin/groups", models.GroupRequest{Name: "operacoes", RoleIDs: []uint{manager.ID}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var group models.Group
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&group))
+	assert.Len(t, group.Roles, 1)
+
+	// Nomes repetidos e roles inexistentes são recusados
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, adminToken, "POST", "/admin/groups", models.GroupRequest{Name: "operacoes"}).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, helpers.Request(r, adminToken, "POST", "/admin/groups", models.GroupRequest{Name: "outro", RoleIDs: []uint{999999}}).Code)
+
+	// Ao entrar no grupo o usuário recebe as roles dele, sem precisar de um novo login
+	member := fmt.Sprintf("/admin/groups/%d/members/%d", group.ID, carol.ID)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "PUT", member, nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, carolToken, "GET", "/admin/roles", nil).Code)
+
+	w = helpers.Request(r, adminToken, "GET", fmt.Sprintf("/admin/groups/%d/members", group.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var members []models.AdminUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&members))
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, carol.ID, members[0].ID)
+	}
+
+	// Uma role usada por um grupo não pode ser removida
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, adminToken, "DELETE", fmt.Sprintf("/admin/roles/%d", manager.ID), nil).Code)
+
+	// Ao sair do grupo as capacidades recebidas deixam de valer
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "DELETE", member, nil).Code)
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, carolToken, "GET", "/admin/roles", nil).Code)
+	assert.Equal(t, http.StatusNotFound, helpers.Request(r, adminToken, "DELETE", member, nil).Code)
+
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "DELETE", fmt.Sprintf("/admin/groups/%d", group.ID), nil).Code)
+	assert.Equal(t, http.StatusNotFound, helpers.Request(r, adminToken, "GET", fmt.Sprintf("/admin/groups/%d", group.ID), nil).Code)
+
+	// Incluir membros concede roles: manage:groups sozinho não basta
+	organizer := createRole("organizador", models.CapabilityManageGroups)
+	dave := helpers.CreateUserWithRole("dave@example.com", organizer.ID)
+	tokens, code = helpers.Login(r, dave.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	w = helpers.Request(r, adminToken, "POST", "/admin/groups", models.GroupRequest{Name: "administradores", RoleIDs: []uint{admin.RoleID}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&group))
+	member = fmt.Sprintf("/admin/groups/%d/members/%d", group.ID, dave.ID)
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, tokens.Token, "PUT", member, nil).Code)
+
+	// Quem administra pelo grupo conta como administrador: o administrador direto pode deixar de sê-lo,
+	// mas o último administrador não pode sair do grupo
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "PUT", member, nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "PUT", fmt.Sprintf("/admin/users/%d/role", admin.ID), models.AssignRoleRequest{RoleID: basic.ID}).Code)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, tokens.Token, "DELETE", member, nil).Code)
+}
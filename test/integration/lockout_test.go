@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginLockout(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	user := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	// Usuário inexistente e senha incorreta recebem a mesma resposta
+	_, code := helpers.Login(r, "ninguem@example.com", "Test123!")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	threshold := settings.LoadSettings().Lockout.AccountThreshold
+	for i := 0; i < threshold; i++ {
+		_, code := helpers.Login(r, user.Email, "senha-errada")
+		assert.Equal(t, http.StatusUnauthorized, code)
+	}
+
+	// Com a conta bloqueada, nem a senha correta é aceita
+	payload := `{"email":"` + user.Email + `","password":"Test123!"}`
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(payload))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	assert.NoError(t, err)
+	assert.Greater(t, retryAfter, 0)
+
+	// Um administrador consulta e remove o bloqueio
+	helpers.CleanupLockouts()
+	admin, code := helpers.Login(r, user.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+
+	for i := 0; i < threshold; i++ {
+		helpers.Login(r, user.Email, "senha-errada")
+	}
+
+	path := "/admin/users/" + strconv.Itoa(int(user.ID)) + "/lockout"
+	w = helpers.Request(r, admin.Token, "GET", path, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var status models.LockoutStatus
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+	assert.True(t, status.Locked)
+	assert.Equal(t, int64(threshold), status.Failures)
+
+	assert.Equal(t, http.StatusOK, helpers.Request(r, admin.Token, "DELETE", path, nil).Code)
+	_, code = helpers.Login(r, user.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfServiceProfile(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+	basic := models.Role{Name: "basico"}
+	database.DB.Create(&basic)
+	erin := helpers.CreateUserWithRole("erin@example.com", basic.ID)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	tokens, code := helpers.Login(r, erin.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	token := tokens.Token
+
+	// O perfil é acessível sem nenhuma capacidade
+	w := helpers.Request(r, token, "GET", "/me", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var me models.AdminUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&me))
+	assert.Equal(t, erin.ID, me.ID)
+
+	w = helpers.Request(r, token, "PATCH", "/me", `{"name": "Erin Silva"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&me))
+	assert.Equal(t, "Erin Silva", me.Name)
+
+	// A role não pode ser alterada pelo próprio usuário
+	w = helpers.Request(r, token, "PATCH", "/me", `{"name": "Erin", "role_id": `+jsonNumber(admin.RoleID)+`}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var stored models.User
+	database.DB.First(&stored, erin.ID)
+	assert.Equal(t, basic.ID, stored.RoleID)
+	assert.Equal(t, "Erin Silva", stored.Name)
+
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, token, "PATCH", "/me", `{"email": "`+admin.Email+`"}`).Code)
+
+	// A troca de senha exige a senha atual
+	w = helpers.Request(r, token, "POST", "/me/password", models.ChangePasswordRequest{CurrentPassword: "errada", NewPassword: "Nova123!senha"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = helpers.Request(r, token, "POST", "/me/password", models.ChangePasswordRequest{CurrentPassword: "Test123!", NewPassword: "curta"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	// Outras sessões são revogadas; a atual continua valendo
+	other, code := helpers.Login(r, erin.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	w = helpers.Request(r, token, "POST", "/me/password", models.ChangePasswordRequest{CurrentPassword: "Test123!", NewPassword: "Nova123!senha"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, token, "GET", "/me", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, other.Token, "GET", "/me", nil).Code)
+
+	_, code = helpers.Login(r, erin.Email, "Test123!")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	_, code = helpers.Login(r, erin.Email, "Nova123!senha")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func jsonNumber(value uint) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -0,0 +1,200 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/pkg/totp"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+// mfaChallenge faz a primeira etapa do login e retorna o desafio de MFA
+func mfaChallenge(t *testing.T, r http.Handler, email string) models.MFAChallengeResponse {
+	w := helpers.Request(r, "", "POST", "/login", models.LoginRequest{Email: email, Password: "Test123!"})
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var challenge models.MFAChallengeResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&challenge))
+	assert.True(t, challenge.MFARequired)
+	assert.NotEmpty(t, challenge.MFAToken)
+	return challenge
+}
+
+// totpCode gera o código TOTP do segredo no intervalo atual deslocado de offset intervalos
+func totpCode(t *testing.T, secret string, offset int64) string {
+	code, err := totp.Code(secret, totp.Step(time.Now())+offset)
+	assert.NoError(t, err)
+	return code
+}
+
+func TestMFATwoStepLogin(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	basic := models.Role{Name: "basico"}
+	database.DB.Create(&basic)
+	user := helpers.CreateUserWithRole("bruce@example.com", basic.ID)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	tokens, code := helpers.Login(r, user.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+
+	// O cadastro só é confirmado com um código gerado pelo segredo pendente
+	w := helpers.Request(r, tokens.Token, "POST", "/me/mfa/enroll", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var enrollment models.MFAEnrollmentResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&enrollment))
+	assert.NotEmpty(t, enrollment.Secret)
+
+	invalid := models.MFACodeRequest{Code: totpCode(t, enrollment.Secret, -10)}
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, tokens.Token, "POST", "/me/mfa/confirm", invalid).Code)
+
+	w = helpers.Request(r, tokens.Token, "POST", "/me/mfa/confirm", models.MFACodeRequest{Code: totpCode(t, enrollment.Secret, -1)})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var recovery models.MFARecoveryCodesResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&recovery))
+	assert.Len(t, recovery.RecoveryCodes, 10)
+
+	// Com o MFA habilitado, a senha devolve apenas o desafio
+	challenge := mfaChallenge(t, r, user.Email)
+	assert.False(t, challenge.EnrollmentRequired)
+
+	wrong := models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: totpCode(t, enrollment.Secret, -10)}
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, "", "POST", "/login/mfa", wrong).Code)
+
+	current := totpCode(t, enrollment.Secret, 0)
+	w = helpers.Request(r, "", "POST", "/login/mfa", models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: current})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var login models.MFALoginResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&login))
+	assert.NotEmpty(t, login.Token)
+	assert.Empty(t, login.RecoveryCodes)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, login.Token, "GET", "/me", nil).Code)
+
+	// O desafio é de uso único e o mesmo código TOTP não vale duas vezes
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, "", "POST", "/login/mfa", models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: current}).Code)
+	challenge = mfaChallenge(t, r, user.Email)
+	replay := models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: current}
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, "", "POST", "/login/mfa", replay).Code)
+
+	// Um código de recuperação conclui o login uma única vez
+	byRecovery := models.MFALoginRequest{MFAToken: challenge.MFAToken, RecoveryCode: recovery.RecoveryCodes[0]}
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/login/mfa", byRecovery).Code)
+
+	challenge = mfaChallenge(t, r, user.Email)
+	byRecovery.MFAToken = challenge.MFAToken
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, "", "POST", "/login/mfa", byRecovery).Code)
+
+	// O desafio é descartado depois de tentativas demais, mesmo que o código seguinte esteja correto
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, "", "POST", "/login/mfa", byRecovery).Code)
+	}
+	exhausted := models.MFALoginRequest{MFAToken: challenge.MFAToken, RecoveryCode: recovery.RecoveryCodes[1]}
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, "", "POST", "/login/mfa", exhausted).Code)
+}
+
+func TestMFARequiredByRole(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	guarded := models.Role{Name: "auditor", RequireMFA: true}
+	database.DB.Create(&guarded)
+	user := helpers.CreateUserWithRole("selina@example.com", guarded.ID)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	// Sem MFA cadastrado, a role exige o cadastro antes de emitir os tokens
+	tokens, code := helpers.Login(r, user.Email, "Test123!")
+	assert.Equal(t, http.StatusAccepted, code)
+	assert.Empty(t, tokens.Token)
+
+	challenge := mfaChallenge(t, r, user.Email)
+	assert.True(t, challenge.EnrollmentRequired)
+
+	notStarted := models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: "123456"}
+	assert.Equal(t, http.StatusBadRequest, helpers.Request(r, "", "POST", "/login/mfa", notStarted).Code)
+
+	w := helpers.Request(r, "", "POST", "/login/mfa/enroll", models.MFAEnrollRequest{MFAToken: challenge.MFAToken})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var enrollment models.MFAEnrollmentResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&enrollment))
+
+	// O primeiro código confirma o cadastro, conclui o login e devolve os códigos de recuperação
+	w = helpers.Request(r, "", "POST", "/login/mfa", models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: totpCode(t, enrollment.Secret, 0)})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var login models.MFALoginResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&login))
+	assert.NotEmpty(t, login.Token)
+	assert.Len(t, login.RecoveryCodes, 10)
+
+	var stored models.User
+	database.DB.First(&stored, user.ID)
+	assert.True(t, stored.MFAEnabled)
+
+	// A role impede que o MFA seja desabilitado
+	disable := models.MFACodeRequest{RecoveryCode: login.RecoveryCodes[0]}
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, login.Token, "POST", "/me/mfa/disable", disable).Code)
+
+	// Os próximos logins pedem o código, sem novo cadastro
+	challenge = mfaChallenge(t, r, user.Email)
+	assert.False(t, challenge.EnrollmentRequired)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/login/mfa", models.MFALoginRequest{MFAToken: challenge.MFAToken, RecoveryCode: login.RecoveryCodes[0]}).Code)
+}
+
+func TestMFALockout(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	basic := models.Role{Name: "basico"}
+	database.DB.Create(&basic)
+	user := helpers.CreateUserWithRole("oswald@example.com", basic.ID)
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+	database.DB.Model(&user).Updates(map[string]interface{}{"mfa_enabled": true, "mfa_secret": secret})
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	// Um desafio obtido antes das falhas continua sujeito ao bloqueio
+	spare := mfaChallenge(t, r, user.Email)
+
+	// A senha correta não zera as falhas: cada desafio novo recebe um código errado
+	threshold := settings.LoadSettings().Lockout.AccountThreshold
+	for i := 0; i < threshold; i++ {
+		challenge := mfaChallenge(t, r, user.Email)
+		wrong := models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: totpCode(t, secret, -10)}
+		assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, "", "POST", "/login/mfa", wrong).Code)
+	}
+
+	// Com a conta bloqueada, nem a senha nem o código correto são aceitos
+	_, code := helpers.Login(r, user.Email, "Test123!")
+	assert.Equal(t, http.StatusTooManyRequests, code)
+	w := helpers.Request(r, "", "POST", "/login/mfa", models.MFALoginRequest{MFAToken: spare.MFAToken, Code: totpCode(t, secret, 0)})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// Concluído o segundo fator, as falhas da conta são zeradas
+	helpers.CleanupLockouts()
+	challenge := mfaChallenge(t, r, user.Email)
+	wrong := models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: totpCode(t, secret, -10)}
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, "", "POST", "/login/mfa", wrong).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/login/mfa", models.MFALoginRequest{MFAToken: challenge.MFAToken, Code: totpCode(t, secret, 0)}).Code)
+	status, err := utils.GetLockout(utils.LockoutScopeAccount, user.Email)
+	assert.NoError(t, err)
+	assert.Zero(t, status.Failures)
+}
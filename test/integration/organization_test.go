@@ -0,0 +1,187 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationScoping(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+	alice := helpers.CreateUserWithRole("alice@example.com", admin.RoleID)
+	bob := helpers.CreateUserWithRole("bob@example.com", admin.RoleID)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	tokens, code := helpers.Login(r, admin.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	platform := tokens.Token
+
+	// No escopo global são criadas as organizações e incluídos os membros
+	createOrganization := func(name string) models.Organization {
+		w := helpers.Request(r, platform, "POST", "/admin/organizations", models.OrganizationRequest{Name: name})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var organization models.Organization
+		json.NewDecoder(w.Body).Decode(&organization)
+		return organization
+	}
+	acme := createOrganization("Acme")
+	globex := createOrganization("Globex")
+
+	addMember := func(organization models.Organization, user models.User) {
+		path := fmt.Sprintf("/admin/organizations/%d/members/%d", organization.ID, user.ID)
+		w := helpers.Request(r, platform, "PUT", path, models.MembershipRequest{RoleID: admin.RoleID})
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	addMember(acme, alice)
+	addMember(globex, bob)
+
+	// Só é possível abrir sessão nas organizações de que o usuário é membro
+	_, code = helpers.LoginOrganization(r, alice.Email, "Test123!", globex.ID)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	tokens, code = helpers.LoginOrganization(r, alice.Email, "Test123!", acme.ID)
+	assert.Equal(t, http.StatusOK, code)
+	aliceToken := tokens.Token
+	tokens, code = helpers.LoginOrganization(r, bob.Email, "Test123!", globex.ID)
+	assert.Equal(t, http.StatusOK, code)
+	bobToken := tokens.Token
+
+	// Na organização, apenas os membros dela são visíveis
+	w := helpers.Request(r, aliceToken, "GET", "/users", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Data []models.AdminUserResponse `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&page))
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, alice.ID, page.Data[0].ID)
+
+	assert.Equal(t, http.StatusNotFound, helpers.Request(r, aliceToken, "GET", fmt.Sprintf("/users/%d", bob.ID), nil).Code)
+	assert.Equal(t, http.StatusNotFound, helpers.Request(r, aliceToken, "DELETE", fmt.Sprintf("/admin/users/%d", bob.ID), nil).Code)
+	assert.Equal(t, http.StatusNotFound, helpers.Request(r, aliceToken, "GET", fmt.Sprintf("/admin/organizations/%d", globex.ID), nil).Code)
+
+	// Roles criadas na organização pertencem a ela e não são vistas pelas demais
+	w = helpers.Request(r, aliceToken, "POST", "/admin/roles", models.RoleRequest{Name: "suporte", Capabilities: []string{models.CapabilityReadUser}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var support models.Role
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&support))
+	if assert.NotNil(t, support.OrganizationID) {
+		assert.Equal(t, acme.ID, *support.OrganizationID)
+	}
+	assert.Equal(t, http.StatusNotFound, helpers.Request(r, bobToken, "GET", fmt.Sprintf("/admin/roles/%d", support.ID), nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, platform, "GET", fmt.Sprintf("/admin/roles/%d", support.ID), nil).Code)
+
+	// Roles globais são apenas para leitura dentro de uma organização
+	w = helpers.Request(r, aliceToken, "PUT", fmt.Sprintf("/admin/roles/%d", admin.RoleID), models.RoleRequest{Name: "outro"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// A organização não pode ficar sem administradores
+	path := fmt.Sprintf("/admin/organizations/%d/members/%d", acme.ID, alice.ID)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, aliceToken, "PUT", path, models.MembershipRequest{RoleID: support.ID}).Code)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, aliceToken, "DELETE", path, nil).Code)
+
+	// O escopo global vê todos os usuários
+	w = helpers.Request(r, platform, "GET", "/users", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&page))
+	assert.Len(t, page.Data, 3)
+}
+
+func TestOrganizationAccountBoundaries(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	tokens, code := helpers.Login(r, admin.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	platform := tokens.Token
+
+	w := helpers.Request(r, platform, "POST", "/admin/roles", models.RoleRequest{Name: "basico", Capabilities: []string{models.CapabilityReadOwnUser}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var basic models.Role
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&basic))
+	w = helpers.Request(r, platform, "POST", "/admin/roles", models.RoleRequest{Name: "gestor", Capabilities: []string{models.CapabilityManageRoles, models.CapabilityUpdateUser}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var manager models.Role
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&manager))
+
+	var acme, globex models.Organization
+	for name, organization := range map[string]*models.Organization{"Acme": &acme, "Globex": &globex} {
+		w = helpers.Request(r, platform, "POST", "/admin/organizations", models.OrganizationRequest{Name: name})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(organization))
+	}
+	addMember := func(organization models.Organization, user models.User, roleID uint) {
+		path := fmt.Sprintf("/admin/organizations/%d/members/%d", organization.ID, user.ID)
+		assert.Equal(t, http.StatusOK, helpers.Request(r, platform, "PUT", path, models.MembershipRequest{RoleID: roleID}).Code)
+	}
+
+	// alice administra a Acme; carol é só da Acme, dave também é da Globex e erin administra o escopo global
+	alice := helpers.CreateUserWithRole("alice@example.com", basic.ID)
+	carol := helpers.CreateUserWithRole("carol@example.com", basic.ID)
+	dave := helpers.CreateUserWithRole("dave@example.com", basic.ID)
+	erin := helpers.CreateUserWithRole("erin@example.com", admin.RoleID)
+	addMember(acme, alice, admin.RoleID)
+	addMember(acme, carol, manager.ID)
+	addMember(acme, dave, basic.ID)
+	addMember(globex, dave, basic.ID)
+	addMember(acme, erin, basic.ID)
+
+	tokens, code = helpers.LoginOrganization(r, alice.Email, "Test123!", acme.ID)
+	assert.Equal(t, http.StatusOK, code)
+	aliceToken := tokens.Token
+
+	// E-mail e senha só são alterados pela organização em contas que são apenas dela
+	userPath := func(user models.User) string { return fmt.Sprintf("/admin/users/%d", user.ID) }
+	assert.Equal(t, http.StatusOK, helpers.Request(r, aliceToken, "PUT", userPath(carol), models.UpdateUserRequest{Email: "carol@acme.example.com"}).Code)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, aliceToken, "PUT", userPath(dave), models.UpdateUserRequest{Email: "dave@acme.example.com"}).Code)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, aliceToken, "PUT", userPath(dave), models.UpdateUserRequest{Password: "Nova123!senha"}).Code)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, aliceToken, "PUT", userPath(erin), models.UpdateUserRequest{Password: "Nova123!senha"}).Code)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, aliceToken, "DELETE", userPath(erin), nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, aliceToken, "PUT", userPath(dave), models.UpdateUserRequest{Name: "Dave"}).Code)
+
+	// As rotas que afetam o sistema inteiro ficam no escopo global, mesmo para quem tem acesso total na organização
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, aliceToken, "GET", "/admin/roles/cache", nil).Code)
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, aliceToken, "DELETE", "/admin/lockouts/ip/203.0.113.7", nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, platform, "GET", "/admin/roles/cache", nil).Code)
+
+	// Em uma organização não se concede mais do que se tem
+	tokens, code = helpers.LoginOrganization(r, "carol@acme.example.com", "Test123!", acme.ID)
+	assert.Equal(t, http.StatusOK, code)
+	carolToken := tokens.Token
+	assert.Equal(t, http.StatusUnprocessableEntity, helpers.Request(r, carolToken, "POST", "/admin/roles", models.RoleRequest{Name: "tudo", Capabilities: []string{"*"}}).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, helpers.Request(r, carolToken, "POST", "/admin/roles", models.RoleRequest{Name: "filha", ParentID: &admin.RoleID}).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, helpers.Request(r, carolToken, "PUT", fmt.Sprintf("/admin/users/%d/role", dave.ID), models.AssignRoleRequest{RoleID: admin.RoleID}).Code)
+	assert.Equal(t, http.StatusCreated, helpers.Request(r, carolToken, "POST", "/admin/roles", models.RoleRequest{Name: "editor", Capabilities: []string{models.CapabilityUpdateUser}}).Code)
+
+	// A organização vê apenas as sessões abertas nela
+	_, code = helpers.LoginOrganization(r, dave.Email, "Test123!", globex.ID)
+	assert.Equal(t, http.StatusOK, code)
+	_, code = helpers.LoginOrganization(r, dave.Email, "Test123!", acme.ID)
+	assert.Equal(t, http.StatusOK, code)
+	w = helpers.Request(r, aliceToken, "GET", userPath(dave)+"/sessions", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var sessions []models.Session
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&sessions))
+	if assert.Len(t, sessions, 1) {
+		assert.Equal(t, acme.ID, sessions[0].OrganizationID)
+	}
+}
@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnershipChecks(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+
+	role := models.Role{
+		Name:         "user",
+		Capabilities: []string{models.CapabilityUpdateOwnUser, models.CapabilityViewOwnTasks},
+	}
+	database.DB.Create(&role)
+	member := helpers.CreateUserWithRole("membro@example.com", role.ID)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	tokens, code := helpers.Login(r, member.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+
+	// O usuário edita o próprio perfil, mas não o de outro usuário
+	w := helpers.Request(r, tokens.Token, "PUT", fmt.Sprintf("/users/%d", member.ID), models.UpdateUserRequest{Name: "Novo Nome"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = helpers.Request(r, tokens.Token, "PUT", fmt.Sprintf("/users/%d", admin.ID), models.UpdateUserRequest{Name: "Invasor"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Nem pode trocar a própria role sem manage:roles
+	w = helpers.Request(r, tokens.Token, "PUT", fmt.Sprintf("/users/%d", member.ID), models.UpdateUserRequest{RoleID: admin.RoleID})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// A própria senha só é trocada em /me/password, com a senha atual
+	w = helpers.Request(r, tokens.Token, "PUT", fmt.Sprintf("/users/%d", member.ID), models.UpdateUserRequest{Password: "Nova123!senha"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	// As rotas administrativas continuam exigindo a capacidade sem escopo
+	w = helpers.Request(r, tokens.Token, "PUT", fmt.Sprintf("/admin/users/%d", member.ID), models.UpdateUserRequest{Name: "Outro Nome"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Com view:tasks:own apenas as tarefas do próprio usuário são retornadas
+	w = helpers.Request(r, tokens.Token, "GET", "/protected/tasks", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var tasks []map[string]string
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&tasks))
+	for _, task := range tasks {
+		assert.Equal(t, fmt.Sprint(member.ID), task["owner_id"])
+	}
+
+	// A senha definida por um administrador revoga as sessões abertas do usuário
+	adminTokens, code := helpers.Login(r, admin.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	w = helpers.Request(r, adminTokens.Token, "PUT", fmt.Sprintf("/admin/users/%d", member.ID), models.UpdateUserRequest{Password: "Nova123!senha"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, tokens.Token, "GET", "/protected/tasks", nil).Code)
+	_, code = helpers.Login(r, member.Email, "Nova123!senha")
+	assert.Equal(t, http.StatusOK, code)
+}
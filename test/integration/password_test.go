@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordReset(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+	mailDir := helpers.UseFileMailer(t)
+
+	user := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	// Um e-mail desconhecido recebe a mesma resposta, sem envio de e-mail
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/password/forgot", models.ForgotPasswordRequest{Email: "unknown@example.com"}).Code)
+	assert.Len(t, helpers.ReadMails(t, mailDir), 0)
+
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/password/forgot", models.ForgotPasswordRequest{Email: user.Email}).Code)
+	messages := helpers.ReadMails(t, mailDir)
+	assert.Len(t, messages, 1)
+
+	token := regexp.MustCompile(`token=([A-Za-z0-9_-]+)`).FindStringSubmatch(helpers.MailBody(t, messages[0]))
+	assert.Len(t, token, 2)
+
+	reset := models.ResetPasswordRequest{Token: token[1], Password: "N0va#Chave2024"}
+	assert.Equal(t, http.StatusOK, helpers.Request(r, "", "POST", "/password/reset", reset).Code)
+
+	// O token é de uso único
+	assert.Equal(t, http.StatusBadRequest, helpers.Request(r, "", "POST", "/password/reset", reset).Code)
+
+	_, code := helpers.Login(r, user.Email, "Test123!")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	_, code = helpers.Login(r, user.Email, "N0va#Chave2024")
+	assert.Equal(t, http.StatusOK, code)
+}
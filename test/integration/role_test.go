@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRoleManagement(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	tokens, code := helpers.Login(r, admin.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+
+	// Cria uma role com capacidades válidas; capacidades mal formadas são rejeitadas
+	w := helpers.Request(r, tokens.Token, "POST", "/admin/roles", models.RoleRequest{Name: "auditor", Capabilities: []string{"sem-recurso"}})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = helpers.Request(r, tokens.Token, "POST", "/admin/roles", models.RoleRequest{Name: "auditor", Capabilities: []string{models.CapabilityReadUser}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var auditor models.Role
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&auditor))
+
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, tokens.Token, "POST", "/admin/roles", models.RoleRequest{Name: "auditor"}).Code)
+
+	// O último administrador não pode perder a role administrativa nem ser excluído
+	rolePath := fmt.Sprintf("/admin/users/%d/role", admin.ID)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, tokens.Token, "PUT", rolePath, models.AssignRoleRequest{RoleID: auditor.ID}).Code)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, tokens.Token, "DELETE", fmt.Sprintf("/admin/users/%d", admin.ID), nil).Code)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, tokens.Token, "PUT", fmt.Sprintf("/admin/roles/%d/capabilities", admin.RoleID), models.RoleCapabilitiesRequest{Capabilities: []string{models.CapabilityReadUser}}).Code)
+
+	// Uma role com usuários não pode ser removida
+	member := models.User{Name: "Membro", Email: "membro@example.com", Password: "x", RoleID: auditor.ID}
+	database.DB.Create(&member)
+	rolePath = fmt.Sprintf("/admin/roles/%d", auditor.ID)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, tokens.Token, "DELETE", rolePath, nil).Code)
+
+	// Depois de trocar a role do usuário, a remoção é permitida
+	assert.Equal(t, http.StatusOK, helpers.Request(r, tokens.Token, "PUT", fmt.Sprintf("/admin/users/%d/role", member.ID), models.AssignRoleRequest{RoleID: admin.RoleID}).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, tokens.Token, "DELETE", rolePath, nil).Code)
+	assert.Equal(t, http.StatusNotFound, helpers.Request(r, tokens.Token, "GET", rolePath, nil).Code)
+
+	// A role atualizada volta com as permissões gravadas
+	w = helpers.Request(r, tokens.Token, "POST", "/admin/permissions", models.PermissionRequest{Name: "relatorios"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var permission models.Permission
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&permission))
+	w = helpers.Request(r, tokens.Token, "PUT", fmt.Sprintf("/admin/roles/%d/permissions", admin.RoleID), models.RolePermissionsRequest{PermissionIDs: []uint{permission.ID}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var updated models.Role
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&updated))
+	if assert.Len(t, updated.Permissions, 1) {
+		assert.Equal(t, "relatorios", updated.Permissions[0].Name)
+	}
+
+	// O nome de uma permissão removida pode ser usado novamente
+	assert.Equal(t, http.StatusOK, helpers.Request(r, tokens.Token, "DELETE", fmt.Sprintf("/admin/permissions/%d", permission.ID), nil).Code)
+	assert.Equal(t, http.StatusCreated, helpers.Request(r, tokens.Token, "POST", "/admin/permissions", models.PermissionRequest{Name: "relatorios"}).Code)
+}
+
+func TestRoleHierarchy(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	tokens, code := helpers.Login(r, admin.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+
+	create := func(role models.RoleRequest) models.Role {
+		w := helpers.Request(r, tokens.Token, "POST", "/admin/roles", role)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created models.Role
+		json.NewDecoder(w.Body).Decode(&created)
+		return created
+	}
+
+	// viewer <- editor <- manager
+	viewer := create(models.RoleRequest{Name: "viewer", Capabilities: []string{models.CapabilityReadUser}})
+	editor := create(models.RoleRequest{Name: "editor", Capabilities: []string{models.CapabilityUpdateUser}, ParentID: &viewer.ID})
+	manager := create(models.RoleRequest{Name: "manager", Capabilities: []string{models.CapabilityDeleteUser}, ParentID: &editor.ID})
+
+	w := helpers.Request(r, tokens.Token, "GET", fmt.Sprintf("/admin/roles/%d/capabilities", manager.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resolved struct {
+		Chain        []string `json:"chain"`
+		Capabilities []string `json:"capabilities"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resolved))
+	assert.Equal(t, []string{"manager", "editor", "viewer"}, resolved.Chain)
+	assert.ElementsMatch(t, []string{models.CapabilityDeleteUser, models.CapabilityUpdateUser, models.CapabilityReadUser}, resolved.Capabilities)
+
+	// viewer herdando de manager formaria um ciclo
+	w = helpers.Request(r, tokens.Token, "PUT", fmt.Sprintf("/admin/roles/%d", viewer.ID), models.RoleRequest{ParentID: &manager.ID})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	// Uma role da qual outras herdam não pode ser removida
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, tokens.Token, "DELETE", fmt.Sprintf("/admin/roles/%d", viewer.ID), nil).Code)
+
+	// Um usuário com a role filha recebe as capacidades herdadas
+	support := create(models.RoleRequest{Name: "support", Capabilities: []string{models.CapabilityManageSessions}})
+	trainee := create(models.RoleRequest{Name: "trainee", ParentID: &support.ID})
+	member := helpers.CreateUserWithRole("membro@example.com", trainee.ID)
+	memberTokens, code := helpers.Login(r, member.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+
+	memberRequest := func(path string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+memberTokens.Token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, memberRequest(fmt.Sprintf("/admin/users/%d/sessions", member.ID)))
+	assert.Equal(t, http.StatusForbidden, memberRequest("/admin/roles"))
+}
+
+func TestLastAdminConcurrentRemoval(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+	second := helpers.CreateUserWithRole("alfred@example.com", admin.RoleID)
+	basic := models.Role{Name: "basico"}
+	database.DB.Create(&basic)
+
+	// Duas transações rebaixam, ao mesmo tempo, cada uma um dos dois últimos administradores
+	results := make([]error, 2)
+	var wg sync.WaitGroup
+	for i, user := range []models.User{admin, second} {
+		wg.Add(1)
+		go func(i int, userID uint) {
+			defer wg.Done()
+			results[i] = database.DB.Transaction(func(tx *gorm.DB) error {
+				return utils.GuardLastAdmin(tx, 0, func() error {
+					time.Sleep(200 * time.Millisecond)
+					return tx.Model(&models.User{}).Where("id = ?", userID).Update("role_id", basic.ID).Error
+				})
+			})
+		}(i, user.ID)
+	}
+	wg.Wait()
+
+	// Apenas uma é confirmada: a outra vê o resultado da primeira e é desfeita
+	var failed int
+	for _, err := range results {
+		if err != nil {
+			assert.ErrorIs(t, err, utils.ErrLastAdmin)
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed)
+
+	count, err := utils.CountAdmins(database.DB, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
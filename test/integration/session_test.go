@@ -0,0 +1,51 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevokeSingleSession(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	user := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	// Dois logins abrem duas sessões independentes
+	laptop, code := helpers.Login(r, user.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	phone, code := helpers.Login(r, user.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+
+	w := helpers.Request(r, laptop.Token, "GET", "/me/sessions", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var sessions []models.Session
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&sessions))
+	assert.Len(t, sessions, 2)
+
+	// Localiza a sessão do outro dispositivo
+	var phoneSession string
+	for _, session := range sessions {
+		if !session.Current {
+			phoneSession = session.ID
+		}
+	}
+	assert.NotEmpty(t, phoneSession)
+
+	// Revogar a sessão do outro dispositivo não afeta a sessão atual
+	assert.Equal(t, http.StatusOK, helpers.Request(r, laptop.Token, "DELETE", "/me/sessions/"+phoneSession, nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, phone.Token, "GET", "/me/sessions", nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, laptop.Token, "GET", "/me/sessions", nil).Code)
+}
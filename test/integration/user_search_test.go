@@ -0,0 +1,164 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchUsers(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+	support := models.Role{Name: "suporte"}
+	database.DB.Create(&support)
+
+	ana := helpers.CreateUserWithRole("ana@example.com", support.ID)
+	database.DB.Model(&ana).Updates(map[string]interface{}{"name": "Ana Souza", "created_at": time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)})
+	bruno := helpers.CreateUserWithRole("bruno@example.com", support.ID)
+	database.DB.Model(&bruno).Updates(map[string]interface{}{"name": "Bruno Lima", "created_at": time.Date(2024, 2, 20, 10, 0, 0, 0, time.UTC), "verified_at": nil})
+	carla := helpers.CreateUserWithRole("carla@souza.dev", admin.RoleID)
+	database.DB.Model(&carla).Updates(map[string]interface{}{"name": "Carla Dias", "created_at": time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)})
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+	tokens, _ := helpers.Login(r, admin.Email, "Test123!")
+
+	list := func(query string) (int, []string, models.PaginatedResponse) {
+		req := httptest.NewRequest("GET", "/users?"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.Token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var response struct {
+			models.PaginatedResponse
+			Data []models.AdminUserResponse `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		var emails []string
+		for _, user := range response.Data {
+			emails = append(emails, user.Email)
+		}
+		return w.Code, emails, response.PaginatedResponse
+	}
+
+	// Busca no nome e no e-mail, sem diferenciar maiúsculas
+	code, emails, page := list("q=SOUZA&sort=name")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{ana.Email, carla.Email}, emails)
+	assert.Equal(t, 2, *page.TotalCount)
+
+	// Os curingas digitados são literais
+	_, emails, _ = list("q=%25")
+	assert.Empty(t, emails)
+
+	_, emails, _ = list("role_id=" + jsonNumber(support.ID) + "&sort=-email")
+	assert.Equal(t, []string{bruno.Email, ana.Email}, emails)
+
+	_, emails, _ = list("verified=false")
+	assert.Equal(t, []string{bruno.Email}, emails)
+
+	database.DB.Model(&ana).Update("status", models.UserSuspended)
+	_, emails, _ = list("status=suspended")
+	assert.Equal(t, []string{ana.Email}, emails)
+
+	// O fim do intervalo em data inclui o dia inteiro
+	_, emails, _ = list("created_from=2024-01-01&created_to=2024-02-20&sort=-created_at")
+	assert.Equal(t, []string{bruno.Email, ana.Email}, emails)
+
+	// Ordenação por vários campos, com paginação estável
+	_, emails, page = list("sort=-created_at,name&limit=2&page=1")
+	assert.Equal(t, []string{admin.Email, carla.Email}, emails)
+	assert.Equal(t, 4, *page.TotalCount)
+	assert.Equal(t, 2, *page.TotalPages)
+
+	// Parâmetros inválidos
+	for _, query := range []string{"sort=password", "status=banido", "verified=sim", "role_id=abc", "created_from=ontem"} {
+		code, _, _ = list(query)
+		assert.Equal(t, http.StatusUnprocessableEntity, code, query)
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"} {
+		user := helpers.CreateUserWithRole(email, admin.RoleID)
+		// Dois usuários com a mesma data, para exercitar o desempate
+		database.DB.Model(&user).Update("created_at", created)
+		if email != "b@example.com" {
+			created = created.AddDate(0, 0, 1)
+		}
+	}
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+	tokens, _ := helpers.Login(r, admin.Email, "Test123!")
+
+	list := func(query string) (int, []string, models.PaginatedResponse) {
+		req := httptest.NewRequest("GET", "/users?"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.Token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var response struct {
+			models.PaginatedResponse
+			Data []models.AdminUserResponse `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		var emails []string
+		for _, user := range response.Data {
+			emails = append(emails, user.Email)
+		}
+		return w.Code, emails, response.PaginatedResponse
+	}
+
+	// Percorre a listagem para frente, sem repetir nem pular usuários
+	code, emails, page := list("pagination=cursor&sort=-created_at&limit=2")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{admin.Email, "d@example.com"}, emails)
+	assert.Equal(t, 5, *page.TotalCount)
+	assert.Nil(t, page.TotalPages)
+	assert.Empty(t, page.PrevCursor)
+	assert.NotEmpty(t, page.NextCursor)
+
+	_, emails, second := list("cursor=" + page.NextCursor + "&sort=-created_at&limit=2&count=false")
+	assert.Equal(t, []string{"b@example.com", "c@example.com"}, emails)
+	assert.Nil(t, second.TotalCount)
+
+	_, emails, third := list("cursor=" + second.NextCursor + "&sort=-created_at&limit=2")
+	assert.Equal(t, []string{"a@example.com"}, emails)
+	assert.Empty(t, third.NextCursor)
+
+	// E volta pela página anterior
+	_, emails, back := list("cursor=" + third.PrevCursor + "&sort=-created_at&limit=2")
+	assert.Equal(t, []string{"b@example.com", "c@example.com"}, emails)
+	_, emails, back = list("cursor=" + back.PrevCursor + "&sort=-created_at&limit=2")
+	assert.Equal(t, []string{admin.Email, "d@example.com"}, emails)
+	assert.Empty(t, back.PrevCursor)
+
+	// O limite é limitado a 100
+	_, _, page = list("limit=1000")
+	assert.Equal(t, 100, page.Limit)
+
+	// O cursor só vale na ordenação em que foi gerado, e verified_at pode ser nulo
+	for _, query := range []string{"cursor=" + second.NextCursor + "&sort=name", "cursor=abc", "pagination=cursor&sort=verified_at", "pagination=offset"} {
+		code, _, _ = list(query)
+		assert.Equal(t, http.StatusUnprocessableEntity, code, query)
+	}
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAccountStatus(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+	moderator := models.Role{Name: "moderador", Capabilities: []string{models.CapabilityManageStatus}}
+	database.DB.Create(&moderator)
+	basic := models.Role{Name: "basico"}
+	database.DB.Create(&basic)
+	gina := helpers.CreateUserWithRole("gina@example.com", moderator.ID)
+	hugo := helpers.CreateUserWithRole("hugo@example.com", basic.ID)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	tokens, _ := helpers.Login(r, gina.Email, "Test123!")
+	ginaToken := tokens.Token
+	hugoTokens, _ := helpers.Login(r, hugo.Email, "Test123!")
+	hugoStatus := fmt.Sprintf("/admin/users/%d/status", hugo.ID)
+
+	// Suspender ou banir exige um motivo
+	assert.Equal(t, http.StatusUnprocessableEntity, helpers.Request(r, ginaToken, "PUT", hugoStatus, models.UserStatusRequest{Status: models.UserSuspended}).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, helpers.Request(r, ginaToken, "PUT", hugoStatus, models.UserStatusRequest{Status: "congelado", Reason: "x"}).Code)
+
+	// A própria conta não pode ser alterada, nem a de quem tem capacidades que quem chama não possui
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, ginaToken, "PUT", fmt.Sprintf("/admin/users/%d/status", gina.ID), models.UserStatusRequest{Status: models.UserPending}).Code)
+	assert.Equal(t, http.StatusForbidden, helpers.Request(r, ginaToken, "PUT", fmt.Sprintf("/admin/users/%d/status", admin.ID), models.UserStatusRequest{Status: models.UserSuspended, Reason: "teste"}).Code)
+
+	// A suspensão revoga as sessões e impede o login
+	w := helpers.Request(r, ginaToken, "PUT", hugoStatus, models.UserStatusRequest{Status: models.UserSuspended, Reason: "Uso indevido"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.AdminUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, models.UserSuspended, response.Status)
+	assert.Equal(t, "Uso indevido", response.StatusReason)
+	assert.NotNil(t, response.StatusChangedAt)
+
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, hugoTokens.Token, "GET", "/me", nil).Code)
+	_, code := helpers.Login(r, hugo.Email, "Test123!")
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// A reativação libera o login
+	assert.Equal(t, http.StatusOK, helpers.Request(r, ginaToken, "PUT", hugoStatus, models.UserStatusRequest{Status: models.UserActive}).Code)
+	hugoTokens, code = helpers.Login(r, hugo.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+
+	// Tokens de contas inativas são recusados mesmo que a sessão ainda exista
+	assert.NoError(t, utils.SyncUserStatus(hugo.ID, models.UserBanned))
+	w = helpers.Request(r, hugoTokens.Token, "GET", "/me", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Conta banida")
+	assert.NoError(t, utils.SyncUserStatus(hugo.ID, models.UserActive))
+
+	// Contas banidas não fazem login
+	assert.Equal(t, http.StatusOK, helpers.Request(r, ginaToken, "PUT", hugoStatus, models.UserStatusRequest{Status: models.UserBanned, Reason: "Fraude"}).Code)
+	_, code = helpers.Login(r, hugo.Email, "Test123!")
+	assert.Equal(t, http.StatusForbidden, code)
+	var stored models.User
+	database.DB.First(&stored, hugo.ID)
+	assert.Equal(t, models.UserBanned, stored.Status)
+
+	// Na inicialização as marcas são refeitas a partir do banco, mesmo que o Redis tenha sido esvaziado
+	assert.NoError(t, utils.SyncUserStatus(hugo.ID, models.UserActive))
+	assert.NoError(t, utils.SyncUserStatus(gina.ID, models.UserSuspended))
+	count, err := utils.SyncInactiveUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	status, err := utils.InactiveStatus(hugo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.UserBanned, status)
+	status, err = utils.InactiveStatus(gina.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, status)
+}
@@ -28,12 +28,17 @@ func TestUserCreation(t *testing.T) {
 	// Setup das rotas
 	routes.SetupRoutes(r)
 
+	// O cadastro público recebe a role padrão
+	defaultRole := models.Role{Name: "user"}
+	database.DB.Create(&defaultRole)
+	admin := models.Role{Name: "admin", Capabilities: []string{"*"}}
+	database.DB.Create(&admin)
+
 	// Criar payload do usuário
-	user := models.User{
+	user := models.CreateUserRequest{
 		Name:     "Test User",
 		Email:    "test@example.com",
-		Password: "Test123!",
-		RoleID:   2,
+		Password: "S3nha#Forte",
 	}
 
 	payload, _ := json.Marshal(user)
@@ -51,15 +56,31 @@ func TestUserCreation(t *testing.T) {
 	// Verificar status code
 	assert.Equal(t, http.StatusCreated, w.Code)
 
-	// Verificar resposta
-	var response models.User
+	// Verificar resposta: nem o hash da senha nem os campos internos são expostos
+	var fields map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &fields))
+	for _, field := range []string{"password", "Password", "DeletedAt", "mfa_secret", "role_id"} {
+		assert.NotContains(t, fields, field)
+	}
+	var response models.UserResponse
 	err := json.NewDecoder(w.Body).Decode(&response)
 	assert.NoError(t, err)
 	assert.Equal(t, user.Name, response.Name)
 	assert.Equal(t, user.Email, response.Email)
 
+	var stored models.User
+	assert.NoError(t, database.DB.First(&stored, response.ID).Error)
+	assert.Equal(t, defaultRole.ID, stored.RoleID)
+
+	// A role não pode ser escolhida no cadastro
+	payload = []byte(`{"name": "Intruso", "email": "intruso@example.com", "password": "S3nha#Forte", "role_id": ` + jsonNumber(admin.ID) + `}`)
+	req = httptest.NewRequest("POST", "/users", bytes.NewBuffer(payload))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
 	// Limpar dados de teste
-	database.DB.Unscoped().Delete(&response)
+	database.DB.Unscoped().Delete(&models.User{}, response.ID)
 }
 
 func TestUserLogin(t *testing.T) {
@@ -98,9 +119,126 @@ func TestUserLogin(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 
 	// Verificar resposta
-	var response map[string]string
+	var response models.TokenResponse
 	err := json.NewDecoder(w.Body).Decode(&response)
 	assert.NoError(t, err)
-	assert.Contains(t, response, "token")
-	assert.NotEmpty(t, response["token"])
+	assert.NotEmpty(t, response.Token)
+	assert.NotEmpty(t, response.RefreshToken)
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	user := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	// Login para obter o primeiro par de tokens
+	payload, _ := json.Marshal(models.LoginRequest{Email: user.Email, Password: "Test123!"})
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(payload))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var login models.TokenResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&login))
+
+	refresh := func(token string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.RefreshRequest{RefreshToken: token})
+		req := httptest.NewRequest("POST", "/refresh_token", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// A primeira renovação retorna um novo refresh token
+	w = refresh(login.RefreshToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var rotated models.TokenResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&rotated))
+	assert.NotEmpty(t, rotated.Token)
+	assert.NotEqual(t, login.RefreshToken, rotated.RefreshToken)
+
+	// Reutilizar o token antigo revoga a família inteira
+	w = refresh(login.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = refresh(rotated.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLogoutRevokesSession(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	user := helpers.CreateTestUser()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	payload, _ := json.Marshal(models.LoginRequest{Email: user.Email, Password: "Test123!"})
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(payload))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var login models.TokenResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&login))
+
+	logout := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/logout", nil)
+		req.Header.Set("Authorization", "Bearer "+login.Token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// O primeiro logout encerra a sessão
+	assert.Equal(t, http.StatusOK, logout().Code)
+
+	// O token revogado não é mais aceito pelo AuthMiddleware
+	assert.Equal(t, http.StatusUnauthorized, logout().Code)
+
+	// O refresh token da sessão também foi revogado
+	body, _ := json.Marshal(models.RefreshRequest{RefreshToken: login.RefreshToken})
+	req = httptest.NewRequest("POST", "/refresh_token", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUserCreationPasswordPolicy(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	// Senha fraca, que contém o nome, e e-mail inválido
+	payload, _ := json.Marshal(models.CreateUserRequest{
+		Name:     "Bruce Wayne",
+		Email:    "bruce-wayne",
+		Password: "bruce",
+	})
+	req := httptest.NewRequest("POST", "/users", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response models.ValidationErrorResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.NotEmpty(t, response.Errors["email"])
+	assert.Contains(t, response.Errors["password"], "A senha não pode conter o seu e-mail ou nome")
+
+	var count int64
+	database.DB.Model(&models.User{}).Count(&count)
+	assert.Zero(t, count)
 }
@@ -0,0 +1,139 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/database"
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/jeffemart/Gotham/internal/settings"
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserTrashLifecycle(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+	basic := models.Role{Name: "basico"}
+	database.DB.Create(&basic)
+	frank := helpers.CreateUserWithRole("frank@example.com", basic.ID)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	adminTokens, _ := helpers.Login(r, admin.Email, "Test123!")
+	adminToken := adminTokens.Token
+	frankTokens, _ := helpers.Login(r, frank.Email, "Test123!")
+
+	// A exclusão leva o usuário para a lixeira e revoga as suas sessões
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "DELETE", fmt.Sprintf("/admin/users/%d", frank.ID), nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, helpers.Request(r, frankTokens.Token, "GET", "/me", nil).Code)
+
+	w := helpers.Request(r, adminToken, "GET", "/admin/users/deleted", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var trash struct {
+		Data []models.DeletedUserResponse `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&trash))
+	if assert.Len(t, trash.Data, 1) {
+		assert.Equal(t, frank.ID, trash.Data[0].ID)
+		assert.True(t, trash.Data[0].PurgeAt.After(trash.Data[0].DeletedAt))
+	}
+
+	// O e-mail de um usuário na lixeira pode ser cadastrado novamente, o que impede a restauração
+	other := helpers.CreateUserWithRole(frank.Email, basic.ID)
+	assert.NotZero(t, other.ID)
+	restore := fmt.Sprintf("/admin/users/%d/restore", frank.ID)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, adminToken, "POST", restore, nil).Code)
+
+	// Uma concessão de outro usuário solicitada e decidida por quem será removido definitivamente
+	now := time.Now()
+	grant := models.RoleGrant{UserID: admin.ID, RoleID: basic.ID, StartsAt: now, ExpiresAt: now.Add(time.Hour),
+		Status: models.GrantApproved, RequestedByID: &other.ID, DecidedByID: &other.ID, DecidedAt: &now}
+	assert.NoError(t, database.DB.Create(&grant).Error)
+
+	// Apenas usuários excluídos podem ser removidos definitivamente
+	purgeOther := fmt.Sprintf("/admin/users/deleted/%d", other.ID)
+	assert.Equal(t, http.StatusNotFound, helpers.Request(r, adminToken, "DELETE", purgeOther, nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "DELETE", fmt.Sprintf("/admin/users/%d", other.ID), nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "DELETE", purgeOther, nil).Code)
+	var count int64
+	database.DB.Unscoped().Model(&models.User{}).Where("id = ?", other.ID).Count(&count)
+	assert.Zero(t, count)
+
+	// A concessão permanece, sem a referência ao usuário removido
+	var kept models.RoleGrant
+	assert.NoError(t, database.DB.First(&kept, grant.ID).Error)
+	assert.Nil(t, kept.RequestedByID)
+	assert.Nil(t, kept.DecidedByID)
+
+	// Com o e-mail livre, a restauração devolve o usuário com a mesma role
+	w = helpers.Request(r, adminToken, "POST", restore, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var restored models.AdminUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&restored))
+	assert.Equal(t, basic.ID, restored.RoleID)
+	_, code := helpers.Login(r, frank.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, http.StatusNotFound, helpers.Request(r, adminToken, "POST", restore, nil).Code)
+
+	// A remoção automática alcança apenas quem está na lixeira há mais que o período de retenção
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "DELETE", fmt.Sprintf("/admin/users/%d", frank.ID), nil).Code)
+	purged, err := utils.PurgeDeletedUsers(time.Now().Add(-24 * time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+
+	database.DB.Unscoped().Model(&models.User{}).Where("id = ?", frank.ID).Update("deleted_at", time.Now().Add(-48*time.Hour))
+	purged, err = utils.PurgeDeletedUsers(time.Now().Add(-24 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	database.DB.Unscoped().Model(&models.User{}).Where("id = ?", frank.ID).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestRestoreUserWithDeletedRole(t *testing.T) {
+	// Setup
+	helpers.SetupTestDB()
+	defer helpers.CleanupTestDB()
+
+	admin := helpers.CreateTestUser()
+	temporary := models.Role{Name: "temporaria"}
+	database.DB.Create(&temporary)
+	selina := helpers.CreateUserWithRole("selina@example.com", temporary.ID)
+
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	adminTokens, _ := helpers.Login(r, admin.Email, "Test123!")
+	adminToken := adminTokens.Token
+
+	// Com o usuário na lixeira, a role deixa de estar em uso e pode ser removida
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "DELETE", fmt.Sprintf("/admin/users/%d", selina.ID), nil).Code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, adminToken, "DELETE", fmt.Sprintf("/admin/roles/%d", temporary.ID), nil).Code)
+
+	// Sem a role padrão, a restauração é recusada em vez de devolver um usuário sem role válida
+	restore := fmt.Sprintf("/admin/users/%d/restore", selina.ID)
+	assert.Equal(t, http.StatusConflict, helpers.Request(r, adminToken, "POST", restore, nil).Code)
+
+	// Com ela, o usuário volta com a role padrão e consegue usar a API
+	fallback := models.Role{Name: settings.LoadSettings().RBAC.DefaultRole}
+	database.DB.Create(&fallback)
+	w := helpers.Request(r, adminToken, "POST", restore, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var restored models.AdminUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&restored))
+	assert.Equal(t, fallback.ID, restored.RoleID)
+
+	tokens, code := helpers.Login(r, selina.Email, "Test123!")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, http.StatusOK, helpers.Request(r, tokens.Token, "GET", "/me", nil).Code)
+}
@@ -0,0 +1,100 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/jeffemart/Gotham/internal/keyring"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := keyring.Rotate(dir, keyring.AlgorithmRS256)
+	assert.NoError(t, err)
+	second, err := keyring.Rotate(dir, keyring.AlgorithmES256)
+	assert.NoError(t, err)
+
+	ring, err := keyring.Load(dir)
+	assert.NoError(t, err)
+
+	// A chave mais recente é a ativa e a anterior continua disponível para verificação
+	assert.Equal(t, second.ID, ring.Active.ID)
+	_, ok := ring.Key(first.ID)
+	assert.True(t, ok)
+
+	jwks := ring.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	for _, jwk := range jwks.Keys {
+		switch jwk.Kid {
+		case first.ID:
+			assert.Equal(t, "RSA", jwk.Kty)
+			assert.Equal(t, "RS256", jwk.Alg)
+			assert.NotEmpty(t, jwk.N)
+		case second.ID:
+			assert.Equal(t, "EC", jwk.Kty)
+			assert.Equal(t, "P-256", jwk.Crv)
+			assert.NotEmpty(t, jwk.X)
+		default:
+			t.Fatalf("kid inesperado: %s", jwk.Kid)
+		}
+	}
+
+	// A chave ativa não pode ser removida; a aposentada pode
+	assert.Error(t, keyring.Retire(dir, second.ID))
+	assert.NoError(t, keyring.Retire(dir, first.ID))
+
+	ring, err = keyring.Load(dir)
+	assert.NoError(t, err)
+	assert.Len(t, ring.Keys(), 1)
+}
+
+func TestKeyringAddAndActivate(t *testing.T) {
+	dir := t.TempDir()
+
+	// Em um diretório vazio, a primeira chave é ativada
+	first, err := keyring.Add(dir, keyring.AlgorithmRS256)
+	assert.NoError(t, err)
+	ring, err := keyring.Load(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, ring.Active.ID)
+
+	// A chave adicionada depois é publicada e verifica tokens, mas não assina
+	second, err := keyring.Add(dir, keyring.AlgorithmES256)
+	assert.NoError(t, err)
+	ring, err = keyring.Load(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, ring.Active.ID)
+	_, ok := ring.Key(second.ID)
+	assert.True(t, ok)
+	assert.Len(t, ring.JWKS().Keys, 2)
+
+	// A ativação troca a chave que assina; kids desconhecidos são recusados
+	_, err = keyring.Activate(dir, "desconhecida")
+	assert.Error(t, err)
+	activated, err := keyring.Activate(dir, second.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, activated.ID)
+	ring, err = keyring.Load(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, ring.Active.ID)
+}
+
+func TestKeyringSignAndVerify(t *testing.T) {
+	for _, algorithm := range []string{keyring.AlgorithmRS256, keyring.AlgorithmES256} {
+		key, err := keyring.Generate(algorithm)
+		assert.NoError(t, err)
+
+		claims := jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Minute).Unix()}
+		tokenString, err := jwt.NewWithClaims(key.SigningMethod(), claims).SignedString(key.PrivateKey)
+		assert.NoError(t, err)
+
+		token, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) {
+			return key.PublicKey(), nil
+		})
+		assert.NoError(t, err)
+		assert.True(t, token.Valid)
+	}
+}
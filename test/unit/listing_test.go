@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	columns := map[string]string{"id": "users.id", "name": "users.name", "created_at": "users.created_at"}
+
+	fields, err := utils.ParseSort("-created_at, name", columns)
+	assert.NoError(t, err)
+	assert.Equal(t, []utils.SortField{{Name: "created_at", Column: "users.created_at", Desc: true}, {Name: "name", Column: "users.name"}}, fields)
+
+	// O desempate completa a ordem e a ordenação volta ao formato da API
+	fields = utils.WithTiebreaker(fields, "id", "users.id")
+	assert.Equal(t, "users.created_at DESC, users.name ASC, users.id ASC", utils.OrderClause(fields))
+	assert.Equal(t, "-created_at,name,id", utils.SortString(fields))
+	assert.Equal(t, "users.created_at ASC, users.name DESC, users.id DESC", utils.OrderClause(utils.ReverseSort(fields)))
+
+	// Sem ordenação, vale apenas o desempate
+	fields, err = utils.ParseSort("", columns)
+	assert.NoError(t, err)
+	assert.Equal(t, "users.id ASC", utils.OrderClause(utils.WithTiebreaker(fields, "id", "users.id")))
+
+	// O desempate não é repetido quando já faz parte da ordenação
+	fields, _ = utils.ParseSort("-id", columns)
+	assert.Equal(t, "users.id DESC", utils.OrderClause(utils.WithTiebreaker(fields, "id", "users.id")))
+
+	// Apenas os campos permitidos, sem repetição
+	_, err = utils.ParseSort("password", columns)
+	assert.Error(t, err)
+	_, err = utils.ParseSort("name;DROP TABLE users", columns)
+	assert.Error(t, err)
+	_, err = utils.ParseSort("name,-name", columns)
+	assert.Error(t, err)
+}
+
+func TestCursor(t *testing.T) {
+	cursor := utils.Cursor{Sort: "-created_at,id", Values: []string{"2024-03-10T15:04:05.123456Z", "42"}, Backward: true}
+	decoded, err := utils.DecodeCursor(utils.EncodeCursor(cursor))
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	_, err = utils.DecodeCursor("não é um cursor")
+	assert.ErrorIs(t, err, utils.ErrInvalidCursor)
+}
+
+func TestKeysetCondition(t *testing.T) {
+	fields := []utils.SortField{{Name: "name", Column: "users.name", Desc: true}, {Name: "id", Column: "users.id"}}
+	condition, args := utils.KeysetCondition(fields, []interface{}{"Ana", uint64(7)})
+	assert.Equal(t, "((users.name < ?) OR (users.name = ? AND users.id > ?))", condition)
+	assert.Equal(t, []interface{}{"Ana", "Ana", uint64(7)}, args)
+}
+
+func TestParseDateBound(t *testing.T) {
+	from, err := utils.ParseDateBound("2024-03-10", false)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), from)
+
+	// Uma data sem horário no fim do intervalo cobre o dia inteiro
+	to, err := utils.ParseDateBound("2024-03-10", true)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), to)
+
+	to, err = utils.ParseDateBound("2024-03-10T15:04:05Z", true)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 15, 4, 5, 0, time.UTC), to)
+
+	_, err = utils.ParseDateBound("10/03/2024", false)
+	assert.Error(t, err)
+}
+
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, "%ana%", utils.LikePattern("ana"))
+	assert.Equal(t, `%100\%\_a\\b%`, utils.LikePattern(`100%_a\b`))
+}
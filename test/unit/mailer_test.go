@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"mime"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/mailer"
+	"github.com/jeffemart/Gotham/test/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := helpers.UseFileMailer(t)
+
+	err := mailer.Send(mailer.Message{
+		To:      []string{"user@example.com"},
+		Subject: "Redefinição de senha",
+		Body:    "Olá, João.\nAcesse o link: http://localhost/password/reset?token=abc",
+	})
+	assert.NoError(t, err)
+
+	messages := helpers.ReadMails(t, dir)
+	assert.Len(t, messages, 1)
+
+	msg := messages[0]
+	assert.Equal(t, "user@example.com", msg.Header.Get("To"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Redefinição de senha", subject)
+
+	body := helpers.MailBody(t, msg)
+	assert.Contains(t, body, "Olá, João.")
+	assert.Contains(t, body, "password/reset?token=abc")
+}
+
+func TestAsyncMail(t *testing.T) {
+	dir := helpers.UseFileMailer(t)
+
+	// O envio em segundo plano não bloqueia quem o inicia; ReadMails aguarda o término
+	release := make(chan struct{})
+	mailer.Async(func() {
+		<-release
+		assert.NoError(t, mailer.Send(mailer.Message{To: []string{"user@example.com"}, Subject: "Assunto", Body: "Corpo"}))
+	})
+	close(release)
+
+	messages := helpers.ReadMails(t, dir)
+	assert.Len(t, messages, 1)
+}
+
+func TestSMTPMailerTimeout(t *testing.T) {
+	// Um servidor que aceita a conexão e nunca responde
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	smtp := &mailer.SMTPMailer{Host: host, Port: port, From: "test@gotham.local", Timeout: 200 * time.Millisecond}
+
+	start := time.Now()
+	err = smtp.Send(mailer.Message{To: []string{"user@example.com"}, Subject: "Assunto", Body: "Corpo"})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
@@ -0,0 +1,19 @@
+package unit
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// As chaves de assinatura dos testes são geradas em um diretório temporário
+	dir, err := os.MkdirTemp("", "gotham-keys")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("JWT_KEYS_DIR", dir)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -0,0 +1,134 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchCapability(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		capability string
+		want       bool
+	}{
+		// Correspondência exata
+		{"read:user", "read:user", true},
+		{"read:user", "read:task", false},
+		{"read:user", "update:user", false},
+
+		// Curinga global
+		{"*", "read:user", true},
+		{"*", "tasks:edit:own", true},
+
+		// Curinga por segmento
+		{"read:*", "read:user", true},
+		{"read:*", "read:user:own", true},
+		{"read:*", "update:user", false},
+		{"*:user", "delete:user", true},
+		{"*:user", "delete:task", false},
+		{"tasks:*:own", "tasks:edit:own", true},
+		{"tasks:*:own", "tasks:edit:any", false},
+		{"*:*", "read:user", true},
+
+		// Padrão mais curto cobre capacidades mais específicas, mas não o contrário
+		{"update:user", "update:user:own", true},
+		{"update:user", "update:user:any", true},
+		{"update:user:own", "update:user", false},
+		{"update:user:own", "update:user:any", false},
+		{"update:user:own", "update:user:own", true},
+
+		// O curinga vale apenas para o segmento inteiro
+		{"read:us*", "read:user", false},
+		{"re*:user", "read:user", false},
+
+		// Entradas vazias ou malformadas nunca correspondem
+		{"", "read:user", false},
+		{"read:user", "", false},
+		{"read::", "read:user:own", false},
+		{"read:user", "read::user", false},
+		{":", "read:user", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"=>"+tt.capability, func(t *testing.T) {
+			assert.Equal(t, tt.want, rbac.MatchCapability(tt.pattern, tt.capability))
+		})
+	}
+}
+
+func TestAllowedWithDenials(t *testing.T) {
+	tests := []struct {
+		name       string
+		granted    []string
+		capability string
+		want       bool
+	}{
+		{"sem concessões", nil, "read:user", false},
+		{"concessão exata", []string{"read:user"}, "read:user", true},
+		{"negação prevalece sobre acesso total", []string{"*", "!delete:user"}, "delete:user", false},
+		{"negação não afeta outras capacidades", []string{"*", "!delete:user"}, "read:user", true},
+		{"negação vale para escopos mais específicos", []string{"*", "!delete:user"}, "delete:user:own", false},
+		{"negação de outro escopo não bloqueia o escopo próprio", []string{"*", "!delete:user:any"}, "delete:user:own", true},
+		{"negação específica bloqueia a exigência mais ampla", []string{"*", "!delete:user:any"}, "delete:user", false},
+		{"negação específica bloqueia o curinga", []string{"delete:*", "!delete:user:any"}, "delete:*", false},
+		{"negação com curinga", []string{"read:*", "!*:secret"}, "read:secret", false},
+		{"ordem das entradas não importa", []string{"!update:user", "update:*"}, "update:user", false},
+		{"apenas negações", []string{"!delete:user"}, "read:user", false},
+		{"negação de tudo", []string{"read:user", "!*"}, "read:user", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rbac.Allowed(tt.granted, tt.capability))
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	allowed, pattern := rbac.Explain([]string{"read:user", "read:*"}, "read:user:own")
+	assert.True(t, allowed)
+	assert.Equal(t, "read:user", pattern)
+
+	allowed, pattern = rbac.Explain([]string{"*", "!delete:user"}, "delete:user:any")
+	assert.False(t, allowed)
+	assert.Equal(t, "!delete:user", pattern)
+
+	allowed, pattern = rbac.Explain([]string{"*", "!delete:user:any"}, "delete:user")
+	assert.False(t, allowed)
+	assert.Equal(t, "!delete:user:any", pattern)
+
+	allowed, pattern = rbac.Explain([]string{"read:user"}, "update:user")
+	assert.False(t, allowed)
+	assert.Empty(t, pattern)
+}
+
+func TestHasCapabilities(t *testing.T) {
+	granted := []string{"read:*", "update:user:own", "!read:secret"}
+
+	assert.True(t, rbac.HasCapabilities(granted))
+	assert.True(t, rbac.HasCapabilities(granted, "read:user", "update:user:own"))
+	assert.False(t, rbac.HasCapabilities(granted, "read:user", "update:user:any"))
+	assert.False(t, rbac.HasCapabilities(granted, "read:secret"))
+}
+
+func TestCovers(t *testing.T) {
+	granted := []string{"read:*", "update:user", "!read:secret"}
+
+	assert.True(t, rbac.Covers(granted, "update:user"))
+	assert.True(t, rbac.Covers(granted, "read:user:own"))
+	assert.True(t, rbac.Covers(granted, "!delete:user"))
+	assert.False(t, rbac.Covers(granted, "read:*"))
+	assert.False(t, rbac.Covers(granted, "delete:user"))
+	assert.False(t, rbac.Covers(granted, "*"))
+	assert.True(t, rbac.Covers([]string{"*"}, "*"))
+	assert.False(t, rbac.Covers([]string{"*", "!delete:user"}, "*"))
+	assert.True(t, rbac.Covers([]string{"*", "!delete:user"}, "read:user"))
+}
+
+func TestResolvedRoleIsAdmin(t *testing.T) {
+	assert.True(t, (&rbac.ResolvedRole{Capabilities: []string{"*"}}).IsAdmin())
+	assert.False(t, (&rbac.ResolvedRole{Capabilities: []string{"*", "!manage:roles"}}).IsAdmin())
+	assert.False(t, (&rbac.ResolvedRole{Capabilities: []string{"*:*"}}).IsAdmin())
+}
@@ -0,0 +1,57 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/jeffemart/Gotham/internal/models"
+	"github.com/jeffemart/Gotham/internal/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnershipDecide(t *testing.T) {
+	tests := []struct {
+		name      string
+		granted   []string
+		userID    uint
+		ownerID   uint
+		allowed   bool
+		wantScope string
+	}{
+		{"dono com :own", []string{models.CapabilityUpdateOwnUser}, 1, 1, true, policy.ScopeOwn},
+		{"outro usuário com :own", []string{models.CapabilityUpdateOwnUser}, 1, 2, false, ""},
+		{"outro usuário com :any", []string{"update:user:any"}, 1, 2, true, policy.ScopeAny},
+		{"dono com :any", []string{"update:user:any"}, 1, 1, true, policy.ScopeAny},
+		{"sem escopo concede os dois", []string{models.CapabilityUpdateUser}, 1, 2, true, policy.ScopeAny},
+		{"curinga", []string{"*"}, 1, 2, true, policy.ScopeAny},
+		{"negação prevalece", []string{"*", "!update:user:any"}, 1, 2, false, ""},
+		{"negação do :any mantém o :own", []string{"update:user", "!update:user:any"}, 1, 1, true, policy.ScopeOwn},
+		{"sem capacidade", []string{models.CapabilityReadUser}, 1, 1, false, ""},
+		{"recurso sem dono", []string{models.CapabilityUpdateOwnUser}, 0, 0, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := policy.Decide(tt.granted, tt.userID, models.CapabilityUpdateUser, tt.ownerID)
+			assert.Equal(t, tt.allowed, decision.Allowed)
+			assert.Equal(t, tt.wantScope, decision.Scope)
+			if !tt.allowed {
+				assert.NotEmpty(t, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestDecideCapability(t *testing.T) {
+	decision := policy.DecideCapability([]string{"manage:*"}, models.CapabilityManageRoles)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, models.CapabilityManageRoles, decision.Capability)
+	assert.Contains(t, decision.Reason, "manage:*")
+
+	decision = policy.DecideCapability([]string{"*", "!manage:roles"}, models.CapabilityManageRoles)
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "!manage:roles")
+
+	decision = policy.DecideCapability([]string{models.CapabilityReadUser}, models.CapabilityManageRoles)
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "ausente")
+}
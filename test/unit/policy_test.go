@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jeffemart/Gotham/internal/middlewares"
+	"github.com/jeffemart/Gotham/internal/policy"
+	"github.com/jeffemart/Gotham/internal/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPolicyCoversRoutes(t *testing.T) {
+	r := mux.NewRouter()
+	routes.SetupRoutes(r)
+
+	assert.NoError(t, policy.Default.Verify(r))
+}
+
+func TestPolicyVerify(t *testing.T) {
+	r := mux.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	r.HandleFunc("/items", ok).Methods("GET")
+	r.HandleFunc("/items/{id:[0-9]+}", ok).Methods("DELETE")
+
+	// Rota sem regra
+	p := policy.MustNew(policy.Rule{Method: "GET", Path: "/items", Public: true})
+	assert.Error(t, p.Verify(r))
+
+	// Regra sem rota
+	p = policy.MustNew(
+		policy.Rule{Method: "GET", Path: "/items", Public: true},
+		policy.Rule{Method: "DELETE", Path: "/items/{id:[0-9]+}", Capabilities: []string{"delete:item"}},
+		policy.Rule{Method: "POST", Path: "/items"},
+	)
+	assert.Error(t, p.Verify(r))
+
+	// AnyMethod cobre todos os métodos da rota
+	p = policy.MustNew(
+		policy.Rule{Method: "GET", Path: "/items", Public: true},
+		policy.Rule{Method: policy.AnyMethod, Path: "/items/{id:[0-9]+}", Roles: []string{"admin"}},
+	)
+	assert.NoError(t, p.Verify(r))
+
+	// Regras duplicadas ou públicas com exigências são rejeitadas
+	_, err := policy.New(policy.Rule{Method: "GET", Path: "/items"}, policy.Rule{Method: "get", Path: "/items"})
+	assert.Error(t, err)
+	_, err = policy.New(policy.Rule{Method: "GET", Path: "/items", Public: true, Roles: []string{"admin"}})
+	assert.Error(t, err)
+	_, err = policy.New(policy.Rule{Method: "GET", Path: "/items", Public: true, GlobalOnly: true})
+	assert.Error(t, err)
+}
+
+func TestAuthorizationMiddleware(t *testing.T) {
+	r := mux.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	r.HandleFunc("/public", ok).Methods("GET")
+	r.HandleFunc("/private", ok).Methods("GET")
+	r.HandleFunc("/unlisted", ok).Methods("GET")
+	r.Use(middlewares.AuthorizationMiddleware(policy.MustNew(
+		policy.Rule{Method: "GET", Path: "/public", Public: true},
+		policy.Rule{Method: "GET", Path: "/private", Capabilities: []string{"read:private"}},
+	)))
+
+	status := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, status("/public"))
+	assert.Equal(t, http.StatusUnauthorized, status("/private"))
+	assert.Equal(t, http.StatusForbidden, status("/unlisted"))
+}
@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeffemart/Gotham/internal/rbac"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleCacheLRU(t *testing.T) {
+	cache := rbac.NewCache(2, time.Minute)
+
+	cache.Set("role:1", &rbac.ResolvedRole{ID: 1})
+	cache.Set("role:2", &rbac.ResolvedRole{ID: 2})
+
+	// Acessar a role 1 a torna a mais recente; a role 2 é descartada ao inserir a 3
+	_, ok := cache.Get("role:1")
+	assert.True(t, ok)
+	cache.Set("role:3", &rbac.ResolvedRole{ID: 3})
+
+	_, ok = cache.Get("role:2")
+	assert.False(t, ok)
+	role, ok := cache.Get("role:1")
+	assert.True(t, ok)
+	assert.Equal(t, uint(1), role.ID)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Size)
+}
+
+func TestRoleCacheTTL(t *testing.T) {
+	now := time.Now()
+	cache := rbac.NewCache(10, time.Minute)
+	cache.SetClock(func() time.Time { return now })
+
+	cache.Set("role:1", &rbac.ResolvedRole{ID: 1})
+	_, ok := cache.Get("role:1")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("role:1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Stats().Size)
+}
+
+func TestRoleCacheUntil(t *testing.T) {
+	now := time.Now()
+	cache := rbac.NewCache(10, time.Minute)
+	cache.SetClock(func() time.Time { return now })
+
+	// Uma concessão que expira antes do TTL encurta a vida da entrada
+	cache.SetIfGenerationUntil(cache.Generation(), "user:1:0:1", &rbac.ResolvedRole{ID: 1}, now.Add(10*time.Second))
+	now = now.Add(9 * time.Second)
+	_, ok := cache.Get("user:1:0:1")
+	assert.True(t, ok)
+	now = now.Add(time.Second)
+	_, ok = cache.Get("user:1:0:1")
+	assert.False(t, ok)
+
+	// Um instante posterior ao TTL não prolonga a entrada
+	cache.SetIfGenerationUntil(cache.Generation(), "user:1:0:1", &rbac.ResolvedRole{ID: 1}, now.Add(time.Hour))
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("user:1:0:1")
+	assert.False(t, ok)
+}
+
+func TestRoleCacheInvalidation(t *testing.T) {
+	cache := rbac.NewCache(10, time.Minute)
+	cache.Set("role:1", &rbac.ResolvedRole{ID: 1})
+
+	// Uma leitura iniciada antes da invalidação não pode repopular o cache
+	generation := cache.Generation()
+	cache.Purge()
+	cache.SetIfGeneration(generation, "role:2", &rbac.ResolvedRole{ID: 2})
+
+	_, ok := cache.Get("role:1")
+	assert.False(t, ok)
+	_, ok = cache.Get("role:2")
+	assert.False(t, ok)
+	assert.Equal(t, uint64(1), cache.Stats().Invalidations)
+
+	cache.SetIfGeneration(cache.Generation(), "role:2", &rbac.ResolvedRole{ID: 2})
+	_, ok = cache.Get("role:2")
+	assert.True(t, ok)
+}
+
+func TestRoleCacheDisabled(t *testing.T) {
+	cache := rbac.NewCache(0, time.Minute)
+	cache.Set("role:1", &rbac.ResolvedRole{ID: 1})
+
+	_, ok := cache.Get("role:1")
+	assert.False(t, ok)
+}
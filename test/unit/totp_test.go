@@ -0,0 +1,58 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeffemart/Gotham/pkg/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+// Segredo "12345678901234567890" dos vetores de teste da RFC 6238, em base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// Vetores SHA1 da RFC 6238, truncados para 6 dígitos
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := totp.Code(rfcSecret, totp.Step(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "instante %d", unix)
+	}
+}
+
+func TestTOTPValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, _ := totp.Code(rfcSecret, totp.Step(now))
+
+	// O código é aceito no intervalo atual e dentro da tolerância
+	step, ok := totp.Validate(rfcSecret, code, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now), step)
+
+	_, ok = totp.Validate(rfcSecret, code, now.Add(totp.Period*time.Second), 1)
+	assert.True(t, ok)
+
+	// Fora da tolerância ou com formato inválido o código é recusado
+	_, ok = totp.Validate(rfcSecret, code, now.Add(3*totp.Period*time.Second), 1)
+	assert.False(t, ok)
+	_, ok = totp.Validate(rfcSecret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+
+	uri := totp.URI("Gotham", "user@example.com", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Gotham:user@example.com?"))
+	assert.Contains(t, uri, "secret="+secret)
+	assert.Contains(t, uri, "issuer=Gotham")
+}
@@ -1,7 +1,6 @@
 package unit
 
 import (
-	"os"
 	"testing"
 	"time"
 
@@ -9,19 +8,20 @@ import (
 	"github.com/jeffemart/Gotham/internal/models"
 	"github.com/jeffemart/Gotham/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestTokenGeneration(t *testing.T) {
 	// Criar usuário de teste
 	user := models.User{
-		ID:    1,
+		Model: gorm.Model{ID: 1},
 		Email: "test@example.com",
 		Role: models.Role{
-			ID:   1,
-			Name: "admin",
+			Model: gorm.Model{ID: 1},
+			Name:  "admin",
 			Permissions: []models.Permission{
-				{ID: 1, Name: "read"},
-				{ID: 2, Name: "write"},
+				{Model: gorm.Model{ID: 1}, Name: "read"},
+				{Model: gorm.Model{ID: 2}, Name: "write"},
 			},
 		},
 	}
@@ -50,11 +50,11 @@ func TestTokenExpiration(t *testing.T) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte(os.Getenv("APP_KEY")))
+	tokenString, err := utils.SignClaims(claims)
+	assert.NoError(t, err)
 
 	// Validar token expirado
-	_, err := utils.ValidateToken(tokenString)
+	_, err = utils.ValidateToken(tokenString)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "token expirado")
 }
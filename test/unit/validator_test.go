@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeffemart/Gotham/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordValidator(t *testing.T) {
+	assert.True(t, validator.PasswordValidator("Gotham2024"))
+	assert.True(t, validator.PasswordValidator("S3nha#Forte"))
+	assert.False(t, validator.PasswordValidator("curta1A"))
+	assert.False(t, validator.PasswordValidator("semnumeroA"))
+	assert.False(t, validator.PasswordValidator("SEMMINUSCULA1"))
+	assert.False(t, validator.PasswordValidator("semmaiuscula1"))
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := validator.PasswordPolicy{
+		MinLength:     10,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		BannedWords:   []string{"senha", "qwerty"},
+	}
+
+	assert.Empty(t, policy.Validate("B@talha-Noturna9", "bruce@wayne.com", "Bruce Wayne"))
+
+	// Cada regra violada gera uma mensagem
+	assert.Len(t, policy.Validate("abc"), 4)
+	assert.Len(t, policy.Validate("MinhaSenha#2024"), 1)
+	assert.Len(t, policy.Validate("Qwerty#12345"), 1)
+
+	// E-mail, parte local do e-mail e partes do nome não podem aparecer na senha
+	assert.Len(t, policy.Validate("Bruce#Forte2024", "bruce@wayne.com", ""), 1)
+	assert.Len(t, policy.Validate("Wayne#Forte2024", "", "Bruce Wayne"), 1)
+
+	// Trechos curtos do nome são ignorados
+	assert.Empty(t, policy.Validate("Al#Fortissima2024", "", "Al Pennyworth"))
+
+	// O limite de bytes do bcrypt vale em qualquer política e conta os bytes, não os caracteres
+	long := "B@talha-Noturna9" + strings.Repeat("x", validator.MaxPasswordBytes-16)
+	assert.Empty(t, policy.Validate(long))
+	assert.Len(t, policy.Validate(long+"x"), 1)
+	assert.Len(t, validator.PasswordPolicy{}.Validate(strings.Repeat("ç", 37)), 1)
+}
+
+func TestEmailValidator(t *testing.T) {
+	assert.True(t, validator.EmailValidator("bruce@wayne.com"))
+	assert.False(t, validator.EmailValidator("bruce@wayne"))
+	assert.False(t, validator.EmailValidator("bruce.wayne.com"))
+}